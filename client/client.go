@@ -3,16 +3,53 @@ package main
 import (
 	"bufio"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"os"
+	"strings"
 
 	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
+// retryServiceConfig retries calls the server could not be reached for. Every
+// call carries its own idempotency key, so a retried purchase or cancellation
+// is only made once.
+const retryServiceConfig = `{
+  "methodConfig": [{
+    "name": [{"service": "trainService.TrainService"}],
+    "retryPolicy": {
+      "maxAttempts": 3,
+      "initialBackoff": "0.5s",
+      "maxBackoff": "2s",
+      "backoffMultiplier": 2,
+      "retryableStatusCodes": ["UNAVAILABLE"]
+    }
+  }]
+}`
+
+// withIdempotencyKey sends a new idempotency key with each call. The server
+// ignores it on calls that change nothing.
+func withIdempotencyKey(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "idempotency-key", hex.EncodeToString(key))
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
 func main() {
-	conn, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+	conn, err := grpc.Dial("localhost:50051", grpc.WithInsecure(),
+		grpc.WithDefaultServiceConfig(retryServiceConfig), grpc.WithUnaryInterceptor(withIdempotencyKey))
 
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
@@ -28,6 +65,34 @@ func main() {
 		fmt.Println("3. Get Users in section")
 		fmt.Println("4. Cancel Ticket")
 		fmt.Println("5. Modify Ticket")
+		fmt.Println("6. Give Seat Swap Consent")
+		fmt.Println("7. Swap Seats")
+		fmt.Println("8. Get Booking by Reference")
+		fmt.Println("9. Cancel Booking by Reference")
+		fmt.Println("10. Get User Bookings")
+		fmt.Println("11. Create Departure")
+		fmt.Println("12. List Departures")
+		fmt.Println("13. Add Station")
+		fmt.Println("14. List Stations")
+		fmt.Println("15. Create Route")
+		fmt.Println("16. List Routes")
+		fmt.Println("17. Quote Fare")
+		fmt.Println("18. Create Promo Code")
+		fmt.Println("19. List Promo Codes")
+		fmt.Println("20. Hold Seat")
+		fmt.Println("21. Confirm Booking")
+		fmt.Println("22. Join Waitlist")
+		fmt.Println("23. Leave Waitlist")
+		fmt.Println("24. Get Waitlist Position")
+		fmt.Println("25. Resize Section")
+		fmt.Println("26. Purchase Group Booking")
+		fmt.Println("27. Get Group Booking")
+		fmt.Println("28. List Layouts")
+		fmt.Println("29. Upgrade Ticket")
+		fmt.Println("30. Check In")
+		fmt.Println("31. Close Check-In")
+		fmt.Println("32. Get No-Show Stats")
+		fmt.Println("33. Watch Availability")
 		fmt.Println("q. Quit")
 
 		choice := inputHelper("Enter your choice: ")
@@ -43,6 +108,62 @@ func main() {
 			cancelTicket(client)
 		case "5":
 			modifyTicket(client)
+		case "6":
+			grantSwapConsent(client)
+		case "7":
+			swapSeats(client)
+		case "8":
+			getBooking(client)
+		case "9":
+			cancelBooking(client)
+		case "10":
+			getUserBookings(client)
+		case "11":
+			createDeparture(client)
+		case "12":
+			listDepartures(client)
+		case "13":
+			addStation(client)
+		case "14":
+			listStations(client)
+		case "15":
+			createRoute(client)
+		case "16":
+			listRoutes(client)
+		case "17":
+			quoteFare(client)
+		case "18":
+			createPromoCode(client)
+		case "19":
+			listPromoCodes(client)
+		case "20":
+			holdSeat(client)
+		case "21":
+			confirmBooking(client)
+		case "22":
+			joinWaitlist(client)
+		case "23":
+			leaveWaitlist(client)
+		case "24":
+			getWaitlistEntry(client)
+		case "25":
+			resizeSection(client)
+		case "26":
+			purchaseGroup(client)
+		case "27":
+			getGroupBooking(client)
+		case "28":
+			listLayouts(client)
+		case "29":
+			upgradeTicket(client)
+		case "30":
+			checkIn(client)
+		case "31":
+			closeCheckIn(client)
+		case "32":
+			getNoShowStats(client)
+		case "33":
+			watchAvailability(client)
 		case "q":
 			fmt.Println("Exiting the program...")
 			os.Exit(0)
@@ -55,6 +176,44 @@ func main() {
 	}
 }
 
+// reportError explains a failed RPC using its status code and error details.
+// Only an unreachable server ends the program; anything else returns to the
+// menu.
+func reportError(rpc string, err error) {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unavailable:
+		log.Fatalf("%s failed, server unavailable: %v", rpc, st.Message())
+	case codes.InvalidArgument:
+		log.Printf("%s failed, invalid request: %v", rpc, st.Message())
+	case codes.NotFound:
+		log.Printf("%s failed, not found: %v", rpc, st.Message())
+	case codes.AlreadyExists:
+		log.Printf("%s failed, already booked: %v", rpc, st.Message())
+	case codes.ResourceExhausted:
+		log.Printf("%s failed, sold out: %v", rpc, st.Message())
+	default:
+		log.Printf("%s failed (%v): %v", rpc, st.Code(), st.Message())
+	}
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, violation := range detail.FieldViolations {
+				log.Printf("  %s: %s", violation.Field, violation.Description)
+			}
+		case *errdetails.PreconditionFailure:
+			for _, violation := range detail.Violations {
+				log.Printf("  %s: %s", violation.Subject, violation.Description)
+			}
+		case *errdetails.QuotaFailure:
+			for _, violation := range detail.Violations {
+				log.Printf("  %s: %s", violation.Subject, violation.Description)
+			}
+		}
+	}
+}
+
 func inputHelper(label string) string {
 	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Print(label)
@@ -62,42 +221,470 @@ func inputHelper(label string) string {
 	return scanner.Text()
 }
 
+// seatInputHelper reads a seat as "row-number", returning nil when the input
+// is empty so the server picks a seat.
+func seatInputHelper(label string) *trainService.Seat {
+	for {
+		input := inputHelper(label)
+		if input == "" {
+			return nil
+		}
+		seat := &trainService.Seat{}
+		if _, err := fmt.Sscanf(input, "%d-%d", &seat.Row, &seat.Number); err == nil {
+			return seat
+		}
+		fmt.Println("Invalid seat, expected row-number (e.g. 2-3).")
+	}
+}
+
+// sectionsInputHelper reads section seat maps as "name:rowsxseats" separated
+// by commas, e.g. "A:5x4,B:10x4", optionally followed by a fare factor as in
+// "A:5x4*1.5".
+func sectionsInputHelper(label string) []*trainService.SectionCapacity {
+	for {
+		var sections []*trainService.SectionCapacity
+		valid := true
+		for _, part := range strings.Split(inputHelper(label), ",") {
+			name, layout, ok := strings.Cut(strings.TrimSpace(part), ":")
+			layout, overbooking, hasOverbooking := strings.Cut(layout, "+")
+			layout, factor, hasFactor := strings.Cut(layout, "*")
+			section := &trainService.SectionCapacity{Section: name}
+			if _, err := fmt.Sscanf(layout, "%dx%d", &section.Rows, &section.SeatsPerRow); !ok || err != nil {
+				valid = false
+				break
+			}
+			if _, err := fmt.Sscanf(factor, "%f", &section.FareFactor); hasFactor && err != nil {
+				valid = false
+				break
+			}
+			if _, err := fmt.Sscanf(overbooking, "%d", &section.OverbookingPercent); hasOverbooking && err != nil {
+				valid = false
+				break
+			}
+			sections = append(sections, section)
+		}
+		if valid {
+			return sections
+		}
+		fmt.Println("Invalid sections, expected name:rowsxseats[*factor][+overbooking%] (e.g. A:5x4*1.5,B:10x4+10).")
+	}
+}
+
+func createDeparture(client trainService.TrainServiceClient) {
+	trainNumber := inputHelper("Enter train number: ")
+	date := inputHelper("Enter date [YYYY-MM-DD]: ")
+	departureTime := inputHelper("Enter departure time [HH:MM]: ")
+	routeID := inputHelper("Enter route ID: ")
+	layout := inputHelper("Enter layout [empty to enter the sections]: ")
+	var sections []*trainService.SectionCapacity
+	if layout == "" {
+		sections = sectionsInputHelper("Enter sections [e.g. A:5x4*1.5,B:10x4+10]: ")
+	}
+
+	createDepartureReq := &trainService.Departure{
+		TrainNumber:   trainNumber,
+		Date:          date,
+		DepartureTime: departureTime,
+		RouteId:       routeID,
+		Layout:        layout,
+		Sections:      sections,
+	}
+	createDepartureResp, err := client.CreateDeparture(context.Background(), createDepartureReq)
+	if err != nil {
+		reportError("CreateDeparture", err)
+		return
+	}
+	log.Printf("CreateDeparture response: %v", createDepartureResp)
+}
+
+func listDepartures(client trainService.TrainServiceClient) {
+	trainNumber := inputHelper("Enter train number [empty for all]: ")
+	date := inputHelper("Enter date [YYYY-MM-DD, empty for all]: ")
+
+	listDeparturesReq := &trainService.ListDeparturesRequest{TrainNumber: trainNumber, Date: date}
+	listDeparturesStream, err := client.ListDepartures(context.Background(), listDeparturesReq)
+	if err != nil {
+		reportError("ListDepartures", err)
+		return
+	}
+	for {
+		departure, err := listDeparturesStream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				reportError("ListDepartures", err)
+				return
+			}
+			break
+		}
+		log.Printf("Departure %v: %v %v %v calling at %v, free seats %v", departure.Id, departure.TrainNumber, departure.Date,
+			departure.DepartureTime, strings.Join(departure.Stops, ", "), departure.AvailableSeats)
+	}
+	log.Println("-----End of departures-----")
+}
+
+func addStation(client trainService.TrainServiceClient) {
+	code := inputHelper("Enter station code: ")
+	name := inputHelper("Enter station name: ")
+
+	addStationReq := &trainService.Station{Code: code, Name: name}
+	addStationResp, err := client.AddStation(context.Background(), addStationReq)
+	if err != nil {
+		reportError("AddStation", err)
+		return
+	}
+	log.Printf("AddStation response: %v", addStationResp)
+}
+
+func listStations(client trainService.TrainServiceClient) {
+	listStationsStream, err := client.ListStations(context.Background(), &trainService.ListStationsRequest{})
+	if err != nil {
+		reportError("ListStations", err)
+		return
+	}
+	for {
+		station, err := listStationsStream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				reportError("ListStations", err)
+				return
+			}
+			break
+		}
+		log.Printf("Station %v: %v", station.Code, station.Name)
+	}
+	log.Println("-----End of stations-----")
+}
+
+func createRoute(client trainService.TrainServiceClient) {
+	id := inputHelper("Enter route ID: ")
+	stops := inputHelper("Enter station codes in calling order [e.g. LON,LIL,PAR]: ")
+	distances := inputHelper("Enter the km between consecutive stops [e.g. 270,225]: ")
+
+	createRouteReq := &trainService.Route{Id: id}
+	for _, stop := range strings.Split(stops, ",") {
+		createRouteReq.Stops = append(createRouteReq.Stops, strings.TrimSpace(stop))
+	}
+	for _, distance := range strings.Split(distances, ",") {
+		var km int32
+		if _, err := fmt.Sscanf(strings.TrimSpace(distance), "%d", &km); err != nil {
+			fmt.Println("Invalid distance, expected whole kilometres.")
+			return
+		}
+		createRouteReq.DistancesKm = append(createRouteReq.DistancesKm, km)
+	}
+	createRouteResp, err := client.CreateRoute(context.Background(), createRouteReq)
+	if err != nil {
+		reportError("CreateRoute", err)
+		return
+	}
+	log.Printf("CreateRoute response: %v", createRouteResp)
+}
+
+func listRoutes(client trainService.TrainServiceClient) {
+	station := inputHelper("Enter station code [empty for all]: ")
+
+	listRoutesStream, err := client.ListRoutes(context.Background(), &trainService.ListRoutesRequest{Station: station})
+	if err != nil {
+		reportError("ListRoutes", err)
+		return
+	}
+	for {
+		route, err := listRoutesStream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				reportError("ListRoutes", err)
+				return
+			}
+			break
+		}
+		log.Printf("Route %v: %v", route.Id, strings.Join(route.Stops, " -> "))
+	}
+	log.Println("-----End of routes-----")
+}
+
+// passengerTypeInputHelper reads a passenger type by name, defaulting to
+// ADULT when the input is empty.
+func passengerTypeInputHelper(label string) trainService.PassengerType {
+	for {
+		input := strings.ToUpper(inputHelper(label))
+		if input == "" {
+			return trainService.PassengerType_ADULT
+		}
+		if value, ok := trainService.PassengerType_value[input]; ok {
+			return trainService.PassengerType(value)
+		}
+		fmt.Println("Invalid passenger type, expected adult, child, senior, student or railcard.")
+	}
+}
+
+// concessionInputHelper reads the user details the concession of
+// passengerType is checked against.
+func concessionInputHelper(user *trainService.User, passengerType trainService.PassengerType) {
+	switch passengerType {
+	case trainService.PassengerType_CHILD, trainService.PassengerType_SENIOR:
+		user.DateOfBirth = inputHelper("Enter date of birth [YYYY-MM-DD]: ")
+	case trainService.PassengerType_STUDENT:
+		user.StudentId = inputHelper("Enter student ID: ")
+	case trainService.PassengerType_RAILCARD:
+		user.RailcardNumber = inputHelper("Enter railcard number: ")
+	}
+}
+
+// logFare prints the original fare, each discount taken off it and the price.
+func logFare(originalFare *trainService.Money, discounts []*trainService.Discount, price *trainService.Money) {
+	log.Printf("Original fare: %s", trainService.FormatMoney(originalFare))
+	for _, discount := range discounts {
+		log.Printf("  - %s %s", trainService.FormatMoney(discount.Amount), discount.Description)
+	}
+	log.Printf("Price: %s", trainService.FormatMoney(price))
+}
+
+// moneyInputHelper reads an amount such as "5.00 EUR".
+func logRefund(refund *trainService.Refund) {
+	if refund == nil {
+		return
+	}
+	log.Printf("Refund: %s, fee %s (%v, %s)", trainService.FormatMoney(refund.Amount), trainService.FormatMoney(refund.Fee), refund.Status, refund.Reason)
+}
+
+func moneyInputHelper(input string) (*trainService.Money, error) {
+	var amount float64
+	var currencyCode string
+	if _, err := fmt.Sscanf(input, "%g %s", &amount, &currencyCode); err != nil {
+		return nil, err
+	}
+	currencyCode = strings.ToUpper(currencyCode)
+	return &trainService.Money{
+		CurrencyCode: currencyCode,
+		MinorUnits:   int64(math.Round(amount * math.Pow10(trainService.CurrencyDigits(currencyCode)))),
+	}, nil
+}
+
+func quoteFare(client trainService.TrainServiceClient) {
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+	from := inputHelper("Enter source station [code on routed departures]: ")
+	to := inputHelper("Enter destination station [code on routed departures]: ")
+	section := inputHelper("Enter section [e.g. A, see List Layouts]: ")
+	passengerType := passengerTypeInputHelper("Enter passenger type [adult, child, senior, student or railcard]: ")
+	promoCode := inputHelper("Enter promo code [empty for none]: ")
+	currency := inputHelper("Enter currency [e.g. GBP, empty for the pricing currency]: ")
+
+	quoteFareReq := &trainService.Ticket{
+		DepartureId:   departureID,
+		From:          from,
+		To:            to,
+		Section:       section,
+		PassengerType: passengerType,
+		PromoCode:     promoCode,
+		Currency:      currency,
+	}
+	quoteFareResp, err := client.QuoteFare(context.Background(), quoteFareReq)
+	if err != nil {
+		reportError("QuoteFare", err)
+		return
+	}
+	logFare(quoteFareResp.OriginalFare, quoteFareResp.Discounts, quoteFareResp.Price)
+	log.Printf("Fare: %s (%v)", trainService.FormatMoney(quoteFareResp.Price), quoteFareResp)
+}
+
+func createPromoCode(client trainService.TrainServiceClient) {
+	code := inputHelper("Enter promo code: ")
+	discount := inputHelper("Enter discount [e.g. 10% or 5.00 EUR]: ")
+	validFrom := inputHelper("Enter first valid date [YYYY-MM-DD, empty for now]: ")
+	validUntil := inputHelper("Enter last valid date [YYYY-MM-DD, empty for no end]: ")
+	maxUses := inputHelper("Enter usage limit [empty for none]: ")
+	routes := inputHelper("Enter route IDs it is valid on [e.g. LON-PAR, empty for all]: ")
+
+	createPromoCodeReq := &trainService.PromoCode{Code: code, ValidFrom: validFrom, ValidUntil: validUntil}
+	if strings.HasSuffix(discount, "%") {
+		if _, err := fmt.Sscanf(strings.TrimSuffix(discount, "%"), "%g", &createPromoCodeReq.PercentOff); err != nil {
+			fmt.Println("Invalid discount, expected a percentage.")
+			return
+		}
+	} else {
+		amountOff, err := moneyInputHelper(discount)
+		if err != nil {
+			fmt.Println("Invalid discount, expected an amount and currency code.")
+			return
+		}
+		createPromoCodeReq.AmountOff = amountOff
+	}
+	if maxUses != "" {
+		if _, err := fmt.Sscanf(maxUses, "%d", &createPromoCodeReq.MaxUses); err != nil {
+			fmt.Println("Invalid usage limit, expected a whole number.")
+			return
+		}
+	}
+	for _, route := range strings.Split(routes, ",") {
+		if route = strings.TrimSpace(route); route != "" {
+			createPromoCodeReq.RouteIds = append(createPromoCodeReq.RouteIds, route)
+		}
+	}
+	createPromoCodeResp, err := client.CreatePromoCode(context.Background(), createPromoCodeReq)
+	if err != nil {
+		reportError("CreatePromoCode", err)
+		return
+	}
+	log.Printf("CreatePromoCode response: %v", createPromoCodeResp)
+}
+
+func listPromoCodes(client trainService.TrainServiceClient) {
+	listPromoCodesStream, err := client.ListPromoCodes(context.Background(), &trainService.ListPromoCodesRequest{})
+	if err != nil {
+		reportError("ListPromoCodes", err)
+		return
+	}
+	for {
+		promo, err := listPromoCodesStream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				reportError("ListPromoCodes", err)
+				return
+			}
+			break
+		}
+		log.Printf("Promo code %v: used %d times (%v)", promo.Code, promo.Uses, promo)
+	}
+	log.Println("-----End of promo codes-----")
+}
+
 func modifyTicket(client trainService.TrainServiceClient) {
 	email := inputHelper("Enter email: ")
-	section := inputHelper("Enter section [A or B]: ")
+	section := inputHelper("Enter section [e.g. A, see List Layouts]: ")
+	seat := seatInputHelper("Enter seat [row-number, empty for any]: ")
 
 	modifyUserSeatReq := &trainService.Ticket{
 		User:    &trainService.User{Email: email},
 		Section: section,
+		Seat:    seat,
 	}
 	modifyUserSeatResp, err := client.ModifyUserSeat(context.Background(), modifyUserSeatReq)
 	if err != nil {
-		log.Fatalf("ModifyUserSeat failed: %v", err)
+		reportError("ModifyUserSeat", err)
+		return
 	}
 	log.Printf("ModifyUserSeat response: %v", modifyUserSeatResp)
 }
 
+func getBooking(client trainService.TrainServiceClient) {
+	reference := inputHelper("Enter booking reference: ")
+
+	getBookingReq := &trainService.BookingReference{Reference: reference}
+	getBookingResp, err := client.GetBooking(context.Background(), getBookingReq)
+	if err != nil {
+		reportError("GetBooking", err)
+		return
+	}
+	log.Printf("GetBooking response: %v", getBookingResp)
+}
+
+func cancelBooking(client trainService.TrainServiceClient) {
+	reference := inputHelper("Enter booking reference: ")
+
+	cancelBookingReq := &trainService.BookingReference{Reference: reference}
+	cancelBookingResp, err := client.CancelBooking(context.Background(), cancelBookingReq)
+	if err != nil {
+		reportError("CancelBooking", err)
+		return
+	}
+	log.Printf("CancelBooking response: %v", cancelBookingResp)
+	logRefund(cancelBookingResp.Refund)
+}
+
+func getUserBookings(client trainService.TrainServiceClient) {
+	email := inputHelper("Enter email: ")
+
+	getUserBookingsReq := &trainService.User{Email: email}
+	getUserBookingsStream, err := client.GetUserBookings(context.Background(), getUserBookingsReq)
+	if err != nil {
+		reportError("GetUserBookings", err)
+		return
+	}
+	for {
+		ticket, err := getUserBookingsStream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				reportError("GetUserBookings", err)
+				return
+			}
+			break
+		}
+		log.Printf("Booking %v: %v", ticket.BookingReference, ticket)
+	}
+	log.Printf("-----End of bookings for: %v-----\n", email)
+}
+
+func grantSwapConsent(client trainService.TrainServiceClient) {
+	email := inputHelper("Enter your email: ")
+	reference := inputHelper("Enter your booking reference: ")
+	other := inputHelper("Enter the email of the passenger to swap with: ")
+
+	grantSwapConsentReq := &trainService.SwapConsentRequest{
+		User:             &trainService.User{Email: email},
+		Other:            &trainService.User{Email: other},
+		BookingReference: reference,
+	}
+	grantSwapConsentResp, err := client.GrantSwapConsent(context.Background(), grantSwapConsentReq)
+	if err != nil {
+		reportError("GrantSwapConsent", err)
+		return
+	}
+	log.Printf("Share this consent token to swap seats: %v", grantSwapConsentResp.Token)
+}
+
+func swapSeats(client trainService.TrainServiceClient) {
+	firstEmail := inputHelper("Enter first passenger's email: ")
+	firstReference := inputHelper("Enter first passenger's booking reference: ")
+	firstConsent := inputHelper("Enter first passenger's consent token: ")
+	secondEmail := inputHelper("Enter second passenger's email: ")
+	secondReference := inputHelper("Enter second passenger's booking reference: ")
+	secondConsent := inputHelper("Enter second passenger's consent token: ")
+
+	swapSeatsReq := &trainService.SwapSeatsRequest{
+		First:                  &trainService.User{Email: firstEmail},
+		FirstConsent:           firstConsent,
+		Second:                 &trainService.User{Email: secondEmail},
+		SecondConsent:          secondConsent,
+		FirstBookingReference:  firstReference,
+		SecondBookingReference: secondReference,
+	}
+	swapSeatsResp, err := client.SwapSeats(context.Background(), swapSeatsReq)
+	if err != nil {
+		reportError("SwapSeats", err)
+		return
+	}
+	log.Printf("SwapSeats response: %v", swapSeatsResp)
+}
+
 func cancelTicket(client trainService.TrainServiceClient) {
 	email := inputHelper("Enter email: ")
 
 	cancelTicketReq := &trainService.User{Email: email}
 	cancelTicketResp, err := client.CancelTicket(context.Background(), cancelTicketReq)
 	if err != nil {
-		log.Fatalf("CancelTicket failed: %v", err)
+		reportError("CancelTicket", err)
+		return
 	}
 	log.Printf("CancelTicket response: %v", cancelTicketResp)
+	logRefund(cancelTicketResp.Refund)
 }
 
 func getUsersBySection(client trainService.TrainServiceClient) {
-	section := inputHelper("Enter section [A or B]: ")
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+	section := inputHelper("Enter section [e.g. A, see List Layouts]: ")
 
-	getUsersBySectionReq := &trainService.Ticket{Section: section}
+	getUsersBySectionReq := &trainService.Ticket{DepartureId: departureID, Section: section}
 	getUsersBySectionStream, err := client.GetUsersBySection(context.Background(), getUsersBySectionReq)
 	if err != nil {
-		log.Fatalf("GetUsersBySection failed: %v", err)
+		reportError("GetUsersBySection", err)
+		return
 	}
 	for {
 		user, err := getUsersBySectionStream.Recv()
+		if err != nil && err != io.EOF {
+			reportError("GetUsersBySection", err)
+			return
+		}
 		if user == nil {
 			log.Printf("-----End of booking list for section: %v-----\n", section)
 		}
@@ -114,33 +701,326 @@ func getReceipt(client trainService.TrainServiceClient) {
 	getReceiptReq := &trainService.User{Email: email}
 	getReceiptResp, err := client.GetReceipt(context.Background(), getReceiptReq)
 	if err != nil {
-		log.Fatalf("GetReceipt failed: %v", err)
+		reportError("GetReceipt", err)
+		return
 	}
 	log.Printf("GetReceipt response: %v", getReceiptResp)
+	logFare(getReceiptResp.OriginalFare, getReceiptResp.Discounts, getReceiptResp.Price)
 }
 
-func purchaseTicket(client trainService.TrainServiceClient) {
-	from := inputHelper("Enter source station: ")
-	to := inputHelper("Enter destination station: ")
+// ticketInputHelper prompts for the journey and passenger of a ticket.
+func ticketInputHelper() *trainService.Ticket {
+	from := inputHelper("Enter source station [code on routed departures]: ")
+	to := inputHelper("Enter destination station [code on routed departures]: ")
 	firstName := inputHelper("Enter first name: ")
 	lastName := inputHelper("Enter last name: ")
 	email := inputHelper("Enter email: ")
-	section := inputHelper("Enter section [A or B]: ")
-
-	purchaseTicketReq := &trainService.Ticket{
-		From: from,
-		To:   to,
-		User: &trainService.User{
-			FirstName: firstName,
-			LastName:  lastName,
-			Email:     email,
-		},
-		Price:   20,
-		Section: section,
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+	section := inputHelper("Enter section [e.g. A, see List Layouts]: ")
+	seat := seatInputHelper("Enter seat [row-number, empty for any]: ")
+	fareClass := inputHelper("Enter fare class [e.g. saver, standard or first, empty for the section's class]: ")
+	passengerType := passengerTypeInputHelper("Enter passenger type [adult, child, senior, student or railcard]: ")
+	accessible := strings.EqualFold(inputHelper("Needs an accessible seat? [y/N]: "), "y")
+	user := &trainService.User{
+		FirstName: firstName,
+		LastName:  lastName,
+		Email:     email,
+	}
+	concessionInputHelper(user, passengerType)
+	promoCode := inputHelper("Enter promo code [empty for none]: ")
+	currency := inputHelper("Enter currency [e.g. GBP, empty for the pricing currency]: ")
+
+	return &trainService.Ticket{
+		DepartureId:    departureID,
+		From:           from,
+		To:             to,
+		User:           user,
+		Section:        section,
+		Seat:           seat,
+		FareClass:      fareClass,
+		PassengerType:  passengerType,
+		AccessibleSeat: accessible,
+		PromoCode:      promoCode,
+		Currency:       currency,
+	}
+}
+
+func purchaseTicket(client trainService.TrainServiceClient) {
+	purchaseTicketReq := ticketInputHelper()
+
+	// Quote first so the passenger pays the price they agreed to.
+	quoteFareResp, err := client.QuoteFare(context.Background(), purchaseTicketReq)
+	if err != nil {
+		reportError("QuoteFare", err)
+		return
+	}
+	logFare(quoteFareResp.OriginalFare, quoteFareResp.Discounts, quoteFareResp.Price)
+	confirm := inputHelper(fmt.Sprintf("Fare is %s, held until %v. Buy? [y/N]: ", trainService.FormatMoney(quoteFareResp.Price), quoteFareResp.ExpiresAt))
+	if !strings.EqualFold(confirm, "y") {
+		fmt.Println("Purchase cancelled.")
+		return
 	}
+	purchaseTicketReq.QuoteToken = quoteFareResp.QuoteToken
+
 	purchaseTicketResp, err := client.PurchaseTicket(context.Background(), purchaseTicketReq)
 	if err != nil {
-		log.Fatalf("PurchaseTicket failed: %v", err)
+		reportError("PurchaseTicket", err)
+		return
 	}
 	log.Printf("PurchaseTicket response: %v", purchaseTicketResp)
 }
+
+func holdSeat(client trainService.TrainServiceClient) {
+	holdSeatReq := ticketInputHelper()
+
+	holdSeatResp, err := client.HoldSeat(context.Background(), holdSeatReq)
+	if err != nil {
+		reportError("HoldSeat", err)
+		return
+	}
+	logFare(holdSeatResp.Ticket.OriginalFare, holdSeatResp.Ticket.Discounts, holdSeatResp.Ticket.Price)
+	log.Printf("Seat %v held until %v, hold token: %s", holdSeatResp.Ticket.Seat, holdSeatResp.ExpiresAt, holdSeatResp.Token)
+}
+
+func confirmBooking(client trainService.TrainServiceClient) {
+	holdToken := inputHelper("Enter hold token: ")
+
+	confirmBookingReq := &trainService.ConfirmBookingRequest{HoldToken: holdToken}
+	confirmBookingResp, err := client.ConfirmBooking(context.Background(), confirmBookingReq)
+	if err != nil {
+		reportError("ConfirmBooking", err)
+		return
+	}
+	log.Printf("ConfirmBooking response: %v", confirmBookingResp)
+}
+
+func logWaitlistEntry(entry *trainService.WaitlistEntry) {
+	if entry.Offer != nil {
+		log.Printf("Seat %v offered until %v, confirm with hold token: %s", entry.Offer.Ticket.Seat, entry.Offer.ExpiresAt, entry.Offer.Token)
+		return
+	}
+	log.Printf("Waitlist entry %s is number %d in the queue", entry.Id, entry.Position)
+}
+
+func joinWaitlist(client trainService.TrainServiceClient) {
+	joinWaitlistReq := ticketInputHelper()
+
+	joinWaitlistResp, err := client.JoinWaitlist(context.Background(), joinWaitlistReq)
+	if err != nil {
+		reportError("JoinWaitlist", err)
+		return
+	}
+	logWaitlistEntry(joinWaitlistResp)
+}
+
+func leaveWaitlist(client trainService.TrainServiceClient) {
+	id := inputHelper("Enter waitlist entry ID: ")
+
+	leaveWaitlistReq := &trainService.WaitlistRequest{Id: id}
+	leaveWaitlistResp, err := client.LeaveWaitlist(context.Background(), leaveWaitlistReq)
+	if err != nil {
+		reportError("LeaveWaitlist", err)
+		return
+	}
+	log.Printf("LeaveWaitlist response: %v", leaveWaitlistResp)
+}
+
+func getWaitlistEntry(client trainService.TrainServiceClient) {
+	id := inputHelper("Enter waitlist entry ID: ")
+
+	getWaitlistEntryReq := &trainService.WaitlistRequest{Id: id}
+	getWaitlistEntryResp, err := client.GetWaitlistEntry(context.Background(), getWaitlistEntryReq)
+	if err != nil {
+		reportError("GetWaitlistEntry", err)
+		return
+	}
+	logWaitlistEntry(getWaitlistEntryResp)
+}
+
+func resizeSection(client trainService.TrainServiceClient) {
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+	section := inputHelper("Enter section: ")
+	resizeSectionReq := &trainService.ResizeSectionRequest{DepartureId: departureID, Section: section}
+	for {
+		layout := inputHelper("Enter new layout [rowsxseats, e.g. 6x4]: ")
+		if _, err := fmt.Sscanf(layout, "%dx%d", &resizeSectionReq.Rows, &resizeSectionReq.SeatsPerRow); err == nil {
+			break
+		}
+		fmt.Println("Invalid layout, expected rowsxseats (e.g. 6x4).")
+	}
+
+	resizeSectionResp, err := client.ResizeSection(context.Background(), resizeSectionReq)
+	if err != nil {
+		reportError("ResizeSection", err)
+		return
+	}
+	log.Printf("ResizeSection response: %v", resizeSectionResp)
+}
+
+func logGroupBooking(booking *trainService.GroupBooking) {
+	log.Printf("Group booking %s, %d passengers, total %s", booking.BookingReference, len(booking.Tickets), trainService.FormatMoney(booking.Price))
+	for _, ticket := range booking.Tickets {
+		log.Printf("  %s %s: seat %v, booking reference %s", ticket.User.FirstName, ticket.User.LastName, ticket.Seat, ticket.BookingReference)
+	}
+}
+
+func purchaseGroup(client trainService.TrainServiceClient) {
+	from := inputHelper("Enter source station [code on routed departures]: ")
+	to := inputHelper("Enter destination station [code on routed departures]: ")
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+	section := inputHelper("Enter section [e.g. A, see List Layouts]: ")
+	fareClass := inputHelper("Enter fare class [e.g. saver, standard or first, empty for the section's class]: ")
+	purchaseGroupReq := &trainService.GroupBookingRequest{DepartureId: departureID, From: from, To: to, Section: section, FareClass: fareClass}
+	for {
+		firstName := inputHelper("Enter passenger first name [empty when done]: ")
+		if firstName == "" {
+			break
+		}
+		lastName := inputHelper("Enter last name: ")
+		email := inputHelper("Enter email: ")
+		passengerType := passengerTypeInputHelper("Enter passenger type [adult, child, senior, student or railcard]: ")
+		accessible := strings.EqualFold(inputHelper("Needs an accessible seat? [y/N]: "), "y")
+		user := &trainService.User{FirstName: firstName, LastName: lastName, Email: email}
+		concessionInputHelper(user, passengerType)
+		purchaseGroupReq.Passengers = append(purchaseGroupReq.Passengers, &trainService.GroupPassenger{User: user, PassengerType: passengerType, AccessibleSeat: accessible})
+	}
+	purchaseGroupReq.PromoCode = inputHelper("Enter promo code [empty for none]: ")
+	purchaseGroupReq.Currency = inputHelper("Enter currency [e.g. GBP, empty for the pricing currency]: ")
+
+	purchaseGroupResp, err := client.PurchaseGroup(context.Background(), purchaseGroupReq)
+	if err != nil {
+		reportError("PurchaseGroup", err)
+		return
+	}
+	logGroupBooking(purchaseGroupResp)
+}
+
+func getGroupBooking(client trainService.TrainServiceClient) {
+	reference := inputHelper("Enter group booking reference: ")
+
+	getGroupBookingReq := &trainService.BookingReference{Reference: reference}
+	getGroupBookingResp, err := client.GetGroupBooking(context.Background(), getGroupBookingReq)
+	if err != nil {
+		reportError("GetGroupBooking", err)
+		return
+	}
+	logGroupBooking(getGroupBookingResp)
+}
+
+func listLayouts(client trainService.TrainServiceClient) {
+	listLayoutsStream, err := client.ListLayouts(context.Background(), &trainService.ListLayoutsRequest{})
+	if err != nil {
+		reportError("ListLayouts", err)
+		return
+	}
+	for {
+		layout, err := listLayoutsStream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				reportError("ListLayouts", err)
+				return
+			}
+			break
+		}
+		log.Printf("Layout %v:", layout.Name)
+		for _, section := range layout.Sections {
+			log.Printf("  Section %v: %s class, %dx%d seats, accessible seats %v", section.Section, section.SeatClass, section.Rows, section.SeatsPerRow, section.AccessibleSeats)
+		}
+	}
+	log.Println("-----End of layouts-----")
+}
+
+func upgradeTicket(client trainService.TrainServiceClient) {
+	reference := inputHelper("Enter booking reference: ")
+	fareClass := inputHelper("Enter fare class to upgrade to [e.g. standard or first]: ")
+	section := inputHelper("Enter section [empty for any selling the class]: ")
+
+	upgradeTicketReq := &trainService.UpgradeTicketRequest{BookingReference: reference, FareClass: fareClass, Section: section}
+	upgradeTicketResp, err := client.UpgradeTicket(context.Background(), upgradeTicketReq)
+	if err != nil {
+		reportError("UpgradeTicket", err)
+		return
+	}
+	log.Printf("UpgradeTicket response: %v", upgradeTicketResp)
+	log.Printf("Now %s class in section %s seat %v, %s paid in total", upgradeTicketResp.FareClass, upgradeTicketResp.Section, upgradeTicketResp.Seat, trainService.FormatMoney(upgradeTicketResp.AmountPaid))
+}
+
+func checkIn(client trainService.TrainServiceClient) {
+	reference := inputHelper("Enter booking reference: ")
+
+	checkInReq := &trainService.BookingReference{Reference: reference}
+	checkInResp, err := client.CheckIn(context.Background(), checkInReq)
+	if err != nil {
+		reportError("CheckIn", err)
+		return
+	}
+	if checkInResp.Seat == nil {
+		log.Printf("Checked in, a seat is given when check-in closes: %v", checkInResp)
+		return
+	}
+	log.Printf("CheckIn response: %v", checkInResp)
+}
+
+func closeCheckIn(client trainService.TrainServiceClient) {
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+
+	closeCheckInReq := &trainService.CloseCheckInRequest{DepartureId: departureID}
+	closeCheckInResp, err := client.CloseCheckIn(context.Background(), closeCheckInReq)
+	if err != nil {
+		reportError("CloseCheckIn", err)
+		return
+	}
+	for _, section := range closeCheckInResp.Departure.CheckIn {
+		log.Printf("Section %s: %d seats, %d booked, %d checked in, %d no-shows, %d bumped, %d rebooked", section.Section, section.Seats,
+			section.Booked, section.CheckedIn, section.NoShows, section.Bumped, section.Rebooked)
+	}
+	for _, ticket := range closeCheckInResp.Bumped {
+		log.Printf("Bumped %s (%s): %s, compensation %s", ticket.User.Email, ticket.BookingReference,
+			ticket.Compensation.Reason, trainService.FormatMoney(ticket.Compensation.Amount))
+		logRefund(ticket.Refund)
+	}
+}
+
+func getNoShowStats(client trainService.TrainServiceClient) {
+	trainNumber := inputHelper("Enter train number [empty for all]: ")
+	section := inputHelper("Enter section [empty for all]: ")
+
+	getNoShowStatsReq := &trainService.NoShowStatsRequest{TrainNumber: trainNumber, Section: section}
+	getNoShowStatsResp, err := client.GetNoShowStats(context.Background(), getNoShowStatsReq)
+	if err != nil {
+		reportError("GetNoShowStats", err)
+		return
+	}
+	log.Printf("%d departures, %d booked, %d checked in, %d no-shows (%.1f%%), %d bumped", getNoShowStatsResp.Departures,
+		getNoShowStatsResp.Booked, getNoShowStatsResp.CheckedIn, getNoShowStatsResp.NoShows, getNoShowStatsResp.NoShowRate*100, getNoShowStatsResp.Bumped)
+}
+
+func watchAvailability(client trainService.TrainServiceClient) {
+	departureID := inputHelper("Enter departure ID [empty for the default train]: ")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watchAvailabilityReq := &trainService.WatchAvailabilityRequest{DepartureId: departureID}
+	watchAvailabilityStream, err := client.WatchAvailability(ctx, watchAvailabilityReq)
+	if err != nil {
+		reportError("WatchAvailability", err)
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			availability, err := watchAvailabilityStream.Recv()
+			if err != nil {
+				if status.Code(err) != codes.Canceled {
+					reportError("WatchAvailability", err)
+				}
+				return
+			}
+			log.Printf("Departure %v section %v: %d seats free", availability.DepartureId, availability.Section, availability.AvailableSeats)
+		}
+	}()
+	inputHelper("Watching free seats, press 'Enter' to stop...\n")
+	cancel()
+	<-done
+}
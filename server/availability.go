@@ -0,0 +1,209 @@
+package main
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// availabilityWatchers fans the free seats of departures out to the
+// WatchAvailability streams watching them. Publishing never blocks: a watcher
+// only keeps the latest count of each section until its stream gets to send
+// it, so a slow client skips counts that are already out of date instead of
+// holding up bookings.
+type availabilityWatchers struct {
+	mu       sync.Mutex
+	watchers map[string]map[*availabilityWatcher]bool // departure ID -> watchers
+	// last has the counts last published for each watched departure.
+	last   map[string]map[string]int32
+	closed bool
+}
+
+// availabilityWatcher is a WatchAvailability stream.
+type availabilityWatcher struct {
+	// pending has the counts not sent yet, by section. It is guarded by the
+	// mutex of the watchers.
+	pending map[string]int32
+	// wake has a value when pending has counts or the watchers are closed.
+	wake chan struct{}
+}
+
+// subscribe starts a watcher on departure that first sends counts, the
+// current free seats of each section.
+func (w *availabilityWatchers) subscribe(departure string, counts map[string]int32) (*availabilityWatcher, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	if w.watchers == nil {
+		w.watchers = map[string]map[*availabilityWatcher]bool{}
+		w.last = map[string]map[string]int32{}
+	}
+	if w.watchers[departure] == nil {
+		w.watchers[departure] = map[*availabilityWatcher]bool{}
+	}
+	pending := make(map[string]int32, len(counts))
+	for section, count := range counts {
+		pending[section] = count
+	}
+	watcher := &availabilityWatcher{pending: pending, wake: make(chan struct{}, 1)}
+	watcher.wake <- struct{}{}
+	w.watchers[departure][watcher] = true
+	w.last[departure] = counts
+	return watcher, nil
+}
+
+// unsubscribe stops watcher on departure.
+func (w *availabilityWatchers) unsubscribe(departure string, watcher *availabilityWatcher) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.watchers[departure], watcher)
+	if len(w.watchers[departure]) == 0 {
+		delete(w.watchers, departure)
+		delete(w.last, departure)
+	}
+}
+
+// watched reports whether any stream watches departure.
+func (w *availabilityWatchers) watched(departure string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.watchers[departure]) > 0
+}
+
+// publish passes the counts of departure that changed since they were last
+// published on to its watchers.
+func (w *availabilityWatchers) publish(departure string, counts map[string]int32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.watchers[departure]) == 0 {
+		return
+	}
+	last := w.last[departure]
+	for section, count := range counts {
+		if previous, ok := last[section]; ok && previous == count {
+			continue
+		}
+		for watcher := range w.watchers[departure] {
+			watcher.pending[section] = count
+			select {
+			case watcher.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+	w.last[departure] = counts
+}
+
+// take returns the counts watcher has not sent yet, or an error once the
+// watchers are closed.
+func (w *availabilityWatchers) take(watcher *availabilityWatcher) (map[string]int32, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	pending := watcher.pending
+	watcher.pending = map[string]int32{}
+	return pending, nil
+}
+
+// close ends every stream, so a graceful stop does not wait for watching
+// clients to hang up.
+func (w *availabilityWatchers) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	for _, watchers := range w.watchers {
+		for watcher := range watchers {
+			select {
+			case watcher.wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// sectionAvailability returns the free seats of each section of departure.
+// Callers must hold s.mu.
+func (s *TrainServer) sectionAvailability(departure *trainService.Departure) map[string]int32 {
+	counts := map[string]int32{}
+	for _, section := range departure.Sections {
+		// Overbooked sections have fewer than no seats free.
+		counts[section.Section] = int32(max(s.store.SeatCount(departure.Id, section.Section), 0))
+	}
+	return counts
+}
+
+// publishAvailability tells the streams watching departure about the seats
+// that were taken or freed on it. Callers must hold s.mu.
+func (s *TrainServer) publishAvailability(departure string) {
+	if !s.watchers.watched(departure) {
+		return
+	}
+	watched, err := s.store.FindDeparture(departure)
+	if err != nil {
+		return
+	}
+	s.watchers.publish(departure, s.sectionAvailability(watched))
+}
+
+// WatchAvailability streams the free seats of each section of a departure,
+// then every change to them until the client hangs up.
+func (s *TrainServer) WatchAvailability(req *trainService.WatchAvailabilityRequest, stream trainService.TrainService_WatchAvailabilityServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+
+	// Counts are only published under the write lock, so none is missed
+	// between reading the current ones and subscribing.
+	s.mu.RLock()
+	departure, err := s.findDeparture(req.DepartureId)
+	if err != nil {
+		s.mu.RUnlock()
+		return err
+	}
+	watcher, err := s.watchers.subscribe(departure.Id, s.sectionAvailability(departure))
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer s.watchers.unsubscribe(departure.Id, watcher)
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-watcher.wake:
+		}
+		counts, err := s.watchers.take(watcher)
+		if err != nil {
+			return err
+		}
+		sections := make([]string, 0, len(counts))
+		for section := range counts {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+		for _, section := range sections {
+			update := &trainService.SeatAvailability{
+				DepartureId:    departure.Id,
+				Section:        section,
+				AvailableSeats: counts[section],
+			}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+	}
+}
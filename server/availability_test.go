@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// availabilityStream hands the updates sent on it to the test, blocking like
+// a slow client when the test does not read them.
+type availabilityStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	updates chan *trainService.SeatAvailability
+}
+
+func (s *availabilityStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *availabilityStream) Send(update *trainService.SeatAvailability) error {
+	select {
+	case s.updates <- update:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// watchAvailability starts watching departure, returning the stream and the
+// channel the error WatchAvailability ends with is sent to. It returns once
+// the stream is subscribed, so no change made after it is missed.
+func watchAvailability(t *testing.T, server *TrainServer, departure string) (*availabilityStream, <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &availabilityStream{ctx: ctx, updates: make(chan *trainService.SeatAvailability)}
+	done := make(chan error, 1)
+	finished := make(chan struct{})
+	go func() {
+		done <- server.WatchAvailability(&trainService.WatchAvailabilityRequest{DepartureId: departure}, stream)
+		close(finished)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-finished
+	})
+	deadline := time.After(5 * time.Second)
+	for !server.watchers.watched(departure) {
+		select {
+		case <-finished:
+			t.Fatalf("Expected to watch %s, got %v", departure, <-done)
+		case <-deadline:
+			t.Fatalf("Expected to watch %s within 5s", departure)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return stream, done
+}
+
+// nextAvailability reads n updates from stream, by section.
+func nextAvailability(t *testing.T, stream *availabilityStream, n int) map[string]int32 {
+	t.Helper()
+	counts := map[string]int32{}
+	for i := 0; i < n; i++ {
+		select {
+		case update := <-stream.updates:
+			counts[update.Section] = update.AvailableSeats
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d availability updates, got %v", n, counts)
+		}
+	}
+	select {
+	case update := <-stream.updates:
+		t.Fatalf("Expected no more availability updates than %v, got %v", counts, update)
+	case <-time.After(20 * time.Millisecond):
+	}
+	return counts
+}
+
+func assertAvailability(t *testing.T, got, expected map[string]int32) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("Expected availability %v, got %v", expected, got)
+	}
+	for section, count := range expected {
+		if got[section] != count {
+			t.Fatalf("Expected availability %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestWatchAvailability(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateReject, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	// Section A has two seats and section B four.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	journey := func(email string) *trainService.Ticket {
+		return testJourney(email, departure.Id, "A")
+	}
+
+	stream, _ := watchAvailability(t, server, departure.Id)
+	assertAvailability(t, nextAvailability(t, stream, 2), map[string]int32{"A": 2, "B": 4})
+
+	if _, err := server.PurchaseTicket(ctx, journey("first@example.com")); err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	assertAvailability(t, nextAvailability(t, stream, 1), map[string]int32{"A": 1})
+
+	moved := &trainService.Ticket{User: &trainService.User{Email: "first@example.com"}, Section: "B"}
+	if _, err := server.ModifyUserSeat(ctx, moved); err != nil {
+		t.Fatalf("ModifyUserSeat failed: %v", err)
+	}
+	assertAvailability(t, nextAvailability(t, stream, 2), map[string]int32{"A": 2, "B": 3})
+
+	if _, err := server.CancelTicket(ctx, &trainService.User{Email: "first@example.com"}); err != nil {
+		t.Fatalf("CancelTicket failed: %v", err)
+	}
+	assertAvailability(t, nextAvailability(t, stream, 1), map[string]int32{"B": 4})
+
+	if _, err := server.HoldSeat(ctx, journey("second@example.com")); err != nil {
+		t.Fatalf("HoldSeat failed: %v", err)
+	}
+	assertAvailability(t, nextAvailability(t, stream, 1), map[string]int32{"A": 1})
+	now = now.Add(defaultHoldTTL)
+	if released, err := server.releaseExpiredHolds(); err != nil || released != 1 {
+		t.Fatalf("Expected the hold to expire, got %d released and %v", released, err)
+	}
+	assertAvailability(t, nextAvailability(t, stream, 1), map[string]int32{"A": 2})
+}
+
+func TestWatchAvailabilitySlowClient(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	stream, _ := watchAvailability(t, server, departure.Id)
+
+	// Nothing reads the stream, so it is stuck sending; bookings go ahead
+	// regardless.
+	for _, email := range []string{"first@example.com", "second@example.com", "third@example.com"} {
+		ticket := testJourney(email, departure.Id, "B")
+		if _, err := server.PurchaseTicket(ctx, ticket); err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+	}
+
+	// The client catches up on the latest count, skipping the ones in between.
+	var counts []int32
+	for len(counts) == 0 || counts[len(counts)-1] != 1 {
+		select {
+		case update := <-stream.updates:
+			if update.Section == "B" {
+				counts = append(counts, update.AvailableSeats)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected section B to reach 1 free seat, got %v", counts)
+		}
+	}
+	for _, count := range counts {
+		if count == 3 || count == 2 {
+			t.Errorf("Expected out of date counts to be skipped, got %v", counts)
+		}
+	}
+}
+
+func TestWatchAvailabilityErrors(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	unknown := &availabilityStream{ctx: ctx}
+	if err := server.WatchAvailability(&trainService.WatchAvailabilityRequest{DepartureId: "unknown"}, unknown); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown departure, got %v", err)
+	}
+	if err := server.WatchAvailability(nil, unknown); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a nil request, got %v", err)
+	}
+
+	stream, done := watchAvailability(t, server, departure.Id)
+	nextAvailability(t, stream, 2)
+	server.watchers.close()
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected Unavailable once the server shuts down, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the stream to end when the server shuts down")
+	}
+	if err := server.WatchAvailability(&trainService.WatchAvailabilityRequest{DepartureId: departure.Id}, unknown); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after shutdown, got %v", err)
+	}
+}
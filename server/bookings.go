@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/iamir0nman/train/trainService"
+)
+
+// Booking references are six characters long, PNR style, and leave out
+// characters that are easily misread (0/O, 1/I).
+const (
+	referenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referenceLength   = 6
+)
+
+// duplicatePolicy decides what PurchaseTicket does when the user already holds
+// a ticket.
+type duplicatePolicy int
+
+const (
+	// duplicateAllow books another ticket next to the existing ones.
+	duplicateAllow duplicatePolicy = iota
+	// duplicateReject refuses the purchase with codes.AlreadyExists.
+	duplicateReject
+	// duplicateReplace cancels the user's earliest ticket in favour of the new
+	// one.
+	duplicateReplace
+)
+
+func parseDuplicatePolicy(name string) (duplicatePolicy, error) {
+	switch name {
+	case "allow":
+		return duplicateAllow, nil
+	case "reject":
+		return duplicateReject, nil
+	case "replace":
+		return duplicateReplace, nil
+	}
+	return 0, fmt.Errorf("unknown duplicate booking policy %q, expected reject, allow or replace", name)
+}
+
+// newBookingReference returns a booking reference not used by any stored
+// ticket or group booking, nor among reserved. Callers must hold s.mu.
+func (s *TrainServer) newBookingReference(reserved ...string) (string, error) {
+	for {
+		buf := make([]byte, referenceLength)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for i, b := range buf {
+			buf[i] = referenceAlphabet[int(b)%len(referenceAlphabet)]
+		}
+		reference := string(buf)
+		if _, err := s.store.FindTicket(reference); !errors.Is(err, errTicketNotFound) {
+			continue
+		}
+		if !containsString(reserved, reference) && len(s.groupTickets(reference)) == 0 {
+			return reference, nil
+		}
+	}
+}
+
+// userTickets returns the tickets booked under email in booking order.
+// Callers must hold s.mu.
+func (s *TrainServer) userTickets(email string) []*trainService.Ticket {
+	var tickets []*trainService.Ticket
+	for _, ticket := range s.store.Tickets() {
+		if ticket.User.Email == email {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// firstUserTicket returns the earliest ticket booked under email, which is
+// the ticket the email based RPCs act on. Callers must hold s.mu.
+func (s *TrainServer) firstUserTicket(email string) (*trainService.Ticket, error) {
+	tickets := s.userTickets(email)
+	if len(tickets) == 0 {
+		return nil, ticketNotFound(email)
+	}
+	return tickets[0], nil
+}
+
+func (s *TrainServer) GetBooking(ctx context.Context, req *trainService.BookingReference) (*trainService.Ticket, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("reference field is empty", field{"reference", req.Reference}); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ticket, err := s.store.FindTicket(req.Reference)
+	if err != nil {
+		return nil, bookingNotFound(req.Reference)
+	}
+	return ticket, nil
+}
+
+func (s *TrainServer) CancelBooking(ctx context.Context, req *trainService.BookingReference) (*trainService.Ticket, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("reference field is empty", field{"reference", req.Reference}); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	ticket, err := s.store.RemoveTicket(req.Reference)
+	if err == nil {
+		s.offerWaitlistSeats(ticket.DepartureId)
+		ticket = s.cancelled(ticket)
+	}
+	s.mu.Unlock()
+	if errors.Is(err, errTicketNotFound) {
+		return nil, bookingNotFound(req.Reference)
+	}
+	if err != nil {
+		return nil, internalError("failed to cancel booking", err)
+	}
+
+	s.refund(ctx, ticket)
+	return ticket, nil
+}
+
+func (s *TrainServer) GetUserBookings(req *trainService.User, stream trainService.TrainService_GetUserBookingsServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+	if err := requireFields("email field is empty", field{"email", req.Email}); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	tickets := s.userTickets(req.Email)
+	s.mu.RUnlock()
+
+	for _, ticket := range tickets {
+		if err := stream.Send(ticket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
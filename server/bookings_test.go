@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBookingReferences(t *testing.T) {
+	store := newTestStore(testLayout, map[string]int{"A": 20, "B": 20})
+	server := &TrainServer{store: store}
+	ctx := context.Background()
+
+	var booked []*trainService.Ticket
+	for _, section := range []string{"A", "B"} {
+		ticket, err := server.PurchaseTicket(ctx, testTicket("deepak@example.com", section))
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		if len(ticket.BookingReference) != referenceLength || strings.Trim(ticket.BookingReference, referenceAlphabet) != "" {
+			t.Errorf("Invalid booking reference %q", ticket.BookingReference)
+		}
+		booked = append(booked, ticket)
+	}
+	if booked[0].BookingReference == booked[1].BookingReference {
+		t.Fatalf("Both tickets got booking reference %s", booked[0].BookingReference)
+	}
+
+	stream := &mockStream{}
+	if err := server.GetUserBookings(&trainService.User{Email: "deepak@example.com"}, stream); err != nil {
+		t.Fatalf("GetUserBookings failed: %v", err)
+	}
+	if len(stream.data) != 2 || !proto.Equal(stream.data[0], booked[0]) || !proto.Equal(stream.data[1], booked[1]) {
+		t.Errorf("Expected bookings %v, got %v", booked, stream.data)
+	}
+
+	ticket, err := server.GetBooking(ctx, &trainService.BookingReference{Reference: booked[1].BookingReference})
+	if err != nil {
+		t.Fatalf("GetBooking failed: %v", err)
+	}
+	if !proto.Equal(ticket, booked[1]) {
+		t.Errorf("Expected ticket %v, got %v", booked[1], ticket)
+	}
+
+	cancelled, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: booked[1].BookingReference})
+	if err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if cancelled.BookingReference != booked[1].BookingReference {
+		t.Errorf("Cancelled the wrong ticket: %v", cancelled)
+	}
+	if store.SeatCount("", "B") != 20 {
+		t.Errorf("Expected the seat to return to section B, got %d", store.SeatCount("", "B"))
+	}
+	if _, err := server.GetBooking(ctx, &trainService.BookingReference{Reference: booked[1].BookingReference}); err == nil {
+		t.Error("Expected an error looking up a cancelled booking, got nil")
+	}
+	if receipt, err := server.GetReceipt(ctx, &trainService.User{Email: "deepak@example.com"}); err != nil || !proto.Equal(receipt, booked[0]) {
+		t.Errorf("Expected the remaining ticket %v, got %v (err %v)", booked[0], receipt, err)
+	}
+
+	moved, err := server.ModifyUserSeat(ctx, &trainService.Ticket{
+		BookingReference: booked[0].BookingReference,
+		Section:          "B",
+	})
+	if err != nil {
+		t.Fatalf("ModifyUserSeat by booking reference failed: %v", err)
+	}
+	if moved.BookingReference != booked[0].BookingReference || moved.Section != "B" {
+		t.Errorf("Expected %s moved to section B, got %v", booked[0].BookingReference, moved)
+	}
+}
+
+func TestBookingReferenceValidation(t *testing.T) {
+	server := &TrainServer{store: newTestStore(testLayout, map[string]int{"A": 20})}
+	ctx := context.Background()
+
+	for _, req := range []*trainService.BookingReference{nil, {}, {Reference: "NOSUCH"}} {
+		if _, err := server.GetBooking(ctx, req); err == nil {
+			t.Errorf("Expected GetBooking(%v) to fail, got nil", req)
+		}
+		if _, err := server.CancelBooking(ctx, req); err == nil {
+			t.Errorf("Expected CancelBooking(%v) to fail, got nil", req)
+		}
+	}
+	for _, req := range []*trainService.User{nil, {}} {
+		if err := server.GetUserBookings(req, &mockStream{}); err == nil {
+			t.Errorf("Expected GetUserBookings(%v) to fail, got nil", req)
+		}
+	}
+}
+
+func TestDuplicateBookingPolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          duplicatePolicy
+		expectedCode    codes.Code
+		expectedTickets int
+	}{
+		{
+			name:            "Reject a second ticket",
+			policy:          duplicateReject,
+			expectedCode:    codes.AlreadyExists,
+			expectedTickets: 1,
+		},
+		{
+			name:            "Allow a second ticket",
+			policy:          duplicateAllow,
+			expectedCode:    codes.OK,
+			expectedTickets: 2,
+		},
+		{
+			name:            "Replace the first ticket",
+			policy:          duplicateReplace,
+			expectedCode:    codes.OK,
+			expectedTickets: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Section A only has room for one ticket, so replacing must reuse
+			// the seat of the ticket it replaces.
+			store := newTestStore(testLayout, map[string]int{"A": 1, "B": 1})
+			server := &TrainServer{store: store, duplicates: tc.policy}
+			ctx := context.Background()
+
+			first, err := server.PurchaseTicket(ctx, testTicket("deepak@example.com", "A"))
+			if err != nil {
+				t.Fatalf("PurchaseTicket failed: %v", err)
+			}
+			second := testTicket("deepak@example.com", "A")
+			if tc.policy == duplicateAllow {
+				second.Section = "B"
+			}
+			_, err = server.PurchaseTicket(ctx, second)
+
+			if status.Code(err) != tc.expectedCode {
+				t.Errorf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+			if len(store.tickets) != tc.expectedTickets {
+				t.Errorf("Expected %d tickets, got %d", tc.expectedTickets, len(store.tickets))
+			}
+			if tc.policy == duplicateReplace {
+				if _, err := store.FindTicket(first.BookingReference); err == nil {
+					t.Error("Expected the first ticket to be replaced")
+				}
+				if store.SeatCount("", "A") != 0 || store.SeatCount("", "B") != 1 {
+					t.Errorf("Expected 0 seats in A and 1 in B, got %d and %d", store.SeatCount("", "A"), store.SeatCount("", "B"))
+				}
+			}
+		})
+	}
+}
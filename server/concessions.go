@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+)
+
+// Age limits of the age based concessions, in whole years on the day of
+// travel.
+const (
+	childMaxAge  = 15
+	seniorMinAge = 60
+)
+
+// checkConcession checks that the passenger of req qualifies for the
+// concession of its passenger type when travelling on departure. Departures
+// without a schedule are taken to travel today.
+func checkConcession(req *trainService.Ticket, departure *trainService.Departure, today time.Time) error {
+	user := req.User
+	if user == nil {
+		user = &trainService.User{}
+	}
+	switch req.PassengerType {
+	case trainService.PassengerType_CHILD, trainService.PassengerType_SENIOR:
+		if err := requireFields("date_of_birth field is empty", field{"user.date_of_birth", user.DateOfBirth}); err != nil {
+			return err
+		}
+		born, err := time.Parse(departureDateLayout, user.DateOfBirth)
+		if err != nil {
+			return invalidField("user.date_of_birth", fmt.Sprintf("date of birth %q is not a valid date (YYYY-MM-DD)", user.DateOfBirth))
+		}
+		travel := today
+		if at, ok := departsAt(departure); ok {
+			travel = at
+		}
+		if born.After(travel) {
+			return invalidField("user.date_of_birth", "date of birth is after the day of travel")
+		}
+		age := ageOn(born, travel)
+		if req.PassengerType == trainService.PassengerType_CHILD && age > childMaxAge {
+			return preconditionFailed("CONCESSION_NOT_ELIGIBLE", "passenger_type",
+				fmt.Sprintf("passenger is %d on the day of travel, child fares are up to age %d", age, childMaxAge))
+		}
+		if req.PassengerType == trainService.PassengerType_SENIOR && age < seniorMinAge {
+			return preconditionFailed("CONCESSION_NOT_ELIGIBLE", "passenger_type",
+				fmt.Sprintf("passenger is %d on the day of travel, senior fares are from age %d", age, seniorMinAge))
+		}
+	case trainService.PassengerType_STUDENT:
+		return requireFields("student_id field is empty", field{"user.student_id", user.StudentId})
+	case trainService.PassengerType_RAILCARD:
+		return requireFields("railcard_number field is empty", field{"user.railcard_number", user.RailcardNumber})
+	}
+	return nil
+}
+
+// ageOn returns the age in whole years on day of someone born on born.
+func ageOn(born, day time.Time) int {
+	age := day.Year() - born.Year()
+	if day.Month() < born.Month() || day.Month() == born.Month() && day.Day() < born.Day() {
+		age--
+	}
+	return age
+}
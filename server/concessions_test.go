@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConcessions(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateAllow,
+		now: func() time.Time { return time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC) }}
+	ctx := context.Background()
+
+	// Travels on Monday 2024-03-04 at an original fare of 79.25.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+
+	tests := []struct {
+		name          string
+		passenger     trainService.PassengerType
+		user          *trainService.User
+		expectedPrice int64
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "Child",
+			passenger:     trainService.PassengerType_CHILD,
+			user:          &trainService.User{DateOfBirth: "2010-03-05"},
+			expectedPrice: 3963,
+		},
+		{
+			name:         "Child turning 16 before travel",
+			passenger:    trainService.PassengerType_CHILD,
+			user:         &trainService.User{DateOfBirth: "2008-03-04"},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:          "Child turning 16 the day after travel",
+			passenger:     trainService.PassengerType_CHILD,
+			user:          &trainService.User{DateOfBirth: "2008-03-05"},
+			expectedPrice: 3963,
+		},
+		{
+			name:         "Senior without date of birth",
+			passenger:    trainService.PassengerType_SENIOR,
+			user:         &trainService.User{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Senior under age",
+			passenger:    trainService.PassengerType_SENIOR,
+			user:         &trainService.User{DateOfBirth: "1964-06-01"},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "Invalid date of birth",
+			passenger:    trainService.PassengerType_SENIOR,
+			user:         &trainService.User{DateOfBirth: "01/06/1950"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:          "Student",
+			passenger:     trainService.PassengerType_STUDENT,
+			user:          &trainService.User{StudentId: "S-1234"},
+			expectedPrice: 5944,
+		},
+		{
+			name:         "Student without student ID",
+			passenger:    trainService.PassengerType_STUDENT,
+			user:         &trainService.User{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Railcard without number",
+			passenger:    trainService.PassengerType_RAILCARD,
+			user:         &trainService.User{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:          "Adult",
+			passenger:     trainService.PassengerType_ADULT,
+			user:          &trainService.User{},
+			expectedPrice: 7925,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ticket := testJourney("concession@example.com", departure.Id, "B")
+			ticket.PassengerType = tc.passenger
+			ticket.User.DateOfBirth, ticket.User.StudentId = tc.user.DateOfBirth, tc.user.StudentId
+			booked, err := server.PurchaseTicket(ctx, ticket)
+
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+			if err != nil {
+				return
+			}
+			defer server.CancelBooking(ctx, &trainService.BookingReference{Reference: booked.BookingReference})
+
+			if !proto.Equal(booked.Price, eur(tc.expectedPrice)) || !proto.Equal(booked.OriginalFare, eur(7925)) {
+				t.Errorf("Expected %v cents of 7925, got %v of %v", tc.expectedPrice, booked.Price, booked.OriginalFare)
+			}
+			if tc.passenger == trainService.PassengerType_ADULT {
+				if len(booked.Discounts) != 0 {
+					t.Errorf("Expected no discounts for an adult, got %v", booked.Discounts)
+				}
+				return
+			}
+			if len(booked.Discounts) != 1 || booked.Discounts[0].Code != tc.passenger.String() ||
+				booked.Discounts[0].Amount.MinorUnits != booked.OriginalFare.MinorUnits-booked.Price.MinorUnits {
+				t.Errorf("Expected the %v concession on the receipt, got %v", tc.passenger, booked.Discounts)
+			}
+		})
+	}
+}
+
+func TestAgeOn(t *testing.T) {
+	born := time.Date(2008, 2, 29, 0, 0, 0, 0, time.UTC)
+	for day, expected := range map[string]int{"2024-02-28": 15, "2024-02-29": 16, "2025-02-28": 16, "2025-03-01": 17} {
+		on, _ := time.Parse(departureDateLayout, day)
+		if age := ageOn(born, on); age != expected {
+			t.Errorf("Expected age %d on %s, got %d", expected, day, age)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultDepartureID is the departure main creates for requests that do not
+// name one, so clients written before departures keep working.
+const defaultDepartureID = "default"
+
+const (
+	departureDateLayout = "2006-01-02"
+	departureTimeLayout = "15:04"
+)
+
+// findDeparture returns the departure with the given ID, or the default
+// departure when id is empty. Callers must hold s.mu.
+func (s *TrainServer) findDeparture(id string) (*trainService.Departure, error) {
+	if id == "" {
+		id = s.defaultDeparture
+	}
+	departure, err := s.store.FindDeparture(id)
+	if err != nil {
+		return nil, departureNotFound(id)
+	}
+	return departure, nil
+}
+
+// departureTickets returns the tickets booked on the given departure.
+func departureTickets(tickets []*trainService.Ticket, departure string) []*trainService.Ticket {
+	var matching []*trainService.Ticket
+	for _, ticket := range tickets {
+		if ticket.DepartureId == departure {
+			matching = append(matching, ticket)
+		}
+	}
+	return matching
+}
+
+// withAvailability returns a copy of departure with the free seats of each
+// section filled in. Callers must hold s.mu.
+func (s *TrainServer) withAvailability(departure *trainService.Departure) *trainService.Departure {
+	departure = proto.Clone(departure).(*trainService.Departure)
+	departure.AvailableSeats = s.sectionAvailability(departure)
+	return departure
+}
+
+// validateDeparture checks the schedule and seat maps of a new departure.
+func validateDeparture(req *trainService.Departure) error {
+	if err := requireFields("(TrainNumber, Date, DepartureTime, RouteId) fields are empty",
+		field{"train_number", req.TrainNumber}, field{"date", req.Date}, field{"departure_time", req.DepartureTime},
+		field{"route_id", req.RouteId}); err != nil {
+		return err
+	}
+	if _, err := time.Parse(departureDateLayout, req.Date); err != nil {
+		return invalidField("date", fmt.Sprintf("date %q is not in YYYY-MM-DD format", req.Date))
+	}
+	if _, err := time.Parse(departureTimeLayout, req.DepartureTime); err != nil {
+		return invalidField("departure_time", fmt.Sprintf("departure time %q is not in HH:MM format", req.DepartureTime))
+	}
+	if req.Layout != "" && len(req.Sections) > 0 {
+		return invalidField("layout", "departure needs either a layout or its sections, not both")
+	}
+	if req.Layout == "" {
+		return validateSections(req.Sections)
+	}
+	return nil
+}
+
+// validateSections checks the seat maps of the sections of a departure or
+// configured layout.
+func validateSections(sections []*trainService.SectionCapacity) error {
+	if len(sections) == 0 {
+		return invalidField("sections", "departure needs at least one section")
+	}
+	seen := map[string]bool{}
+	for i, section := range sections {
+		name := fmt.Sprintf("sections[%d]", i)
+		if section.Section == "" {
+			return invalidField(name+".section", "section name is required")
+		}
+		if seen[section.Section] {
+			return invalidField(name+".section", fmt.Sprintf("section %s is listed twice", section.Section))
+		}
+		seen[section.Section] = true
+		if section.Rows <= 0 || section.SeatsPerRow <= 0 {
+			return invalidField(name, fmt.Sprintf("section %s needs at least one row and one seat per row", section.Section))
+		}
+		if section.FareFactor < 0 {
+			return invalidField(name+".fare_factor", fmt.Sprintf("section %s has a negative fare factor", section.Section))
+		}
+		if section.OverbookingPercent < 0 || section.OverbookingPercent > 100 {
+			return invalidField(name+".overbooking_percent", fmt.Sprintf("section %s needs an overbooking percentage between 0 and 100", section.Section))
+		}
+		layout := seatLayout{Rows: section.Rows, SeatsPerRow: section.SeatsPerRow}
+		accessible := map[seatKey]bool{}
+		for _, seat := range section.AccessibleSeats {
+			if !layout.contains(seat) {
+				return invalidField(name+".accessible_seats", fmt.Sprintf("accessible %s does not exist in section %s", seatLabel(seat), section.Section))
+			}
+			if accessible[keyOf(seat)] {
+				return invalidField(name+".accessible_seats", fmt.Sprintf("accessible %s is listed twice in section %s", seatLabel(seat), section.Section))
+			}
+			accessible[keyOf(seat)] = true
+		}
+	}
+	return nil
+}
+
+func (s *TrainServer) CreateDeparture(ctx context.Context, req *trainService.Departure) (*trainService.Departure, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := validateDeparture(req); err != nil {
+		return nil, err
+	}
+
+	departure := &trainService.Departure{
+		Id:            fmt.Sprintf("%s-%s-%s", req.TrainNumber, strings.ReplaceAll(req.Date, "-", ""), strings.ReplaceAll(req.DepartureTime, ":", "")),
+		TrainNumber:   req.TrainNumber,
+		Date:          req.Date,
+		DepartureTime: req.DepartureTime,
+		RouteId:       req.RouteId,
+		Layout:        req.Layout,
+	}
+	if req.Layout != "" {
+		sections, ok := s.layoutTable().sections(req.Layout)
+		if !ok {
+			return nil, invalidField("layout", fmt.Sprintf("layout %s is not configured", req.Layout))
+		}
+		departure.Sections = sections
+	}
+	for _, section := range req.Sections {
+		section = proto.Clone(section).(*trainService.SectionCapacity)
+		section.SeatClass = normaliseSeatClass(section.SeatClass)
+		departure.Sections = append(departure.Sections, section)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The departure keeps its own copy of the stops and distances, so its
+	// seat inventory and fares do not depend on the route staying the same.
+	route, err := s.store.FindRoute(req.RouteId)
+	if err != nil {
+		return nil, invalidField("route_id", fmt.Sprintf("route %s does not exist", req.RouteId))
+	}
+	departure.Stops = append([]string(nil), route.Stops...)
+	departure.DistancesKm = append([]int32(nil), route.DistancesKm...)
+
+	err = s.store.AddDeparture(departure)
+	if errors.Is(err, errDepartureExists) {
+		return nil, withDetails(codes.AlreadyExists, fmt.Sprintf("departure %s already exists", departure.Id),
+			&errdetails.ResourceInfo{ResourceType: "departure", ResourceName: departure.Id})
+	}
+	if err != nil {
+		return nil, internalError("failed to save departure", err)
+	}
+	return s.withAvailability(departure), nil
+}
+
+// ResizeSection grows a section of a departure, e.g. when a coach is added,
+// and offers the new seats to the waitlist. Sections cannot shrink, as seats
+// already sold would be lost.
+func (s *TrainServer) ResizeSection(ctx context.Context, req *trainService.ResizeSectionRequest) (*trainService.Departure, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("section field is empty", field{"section", req.Section}); err != nil {
+		return nil, err
+	}
+	if req.Rows <= 0 || req.SeatsPerRow <= 0 {
+		return nil, invalidField("rows", fmt.Sprintf("section %s needs at least one row and one seat per row", req.Section))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	departure, err := s.findDeparture(req.DepartureId)
+	if err != nil {
+		return nil, err
+	}
+	// Check-in closing settles who travels in the seats there are.
+	if err := checkInOpen(departure); err != nil {
+		return nil, err
+	}
+	updated := proto.Clone(departure).(*trainService.Departure)
+	var section *trainService.SectionCapacity
+	for _, capacity := range updated.Sections {
+		if capacity.Section == req.Section {
+			section = capacity
+		}
+	}
+	if section == nil {
+		return nil, invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", req.Section, departure.Id))
+	}
+	if req.Rows < section.Rows || req.SeatsPerRow < section.SeatsPerRow {
+		return nil, preconditionFailed("SECTION_SHRINK", "section:"+req.Section,
+			fmt.Sprintf("section %s has %d rows of %d seats and can only grow", req.Section, section.Rows, section.SeatsPerRow))
+	}
+	section.Rows, section.SeatsPerRow = req.Rows, req.SeatsPerRow
+
+	if err := s.store.UpdateDeparture(updated); err != nil {
+		return nil, internalError("failed to save departure", err)
+	}
+	s.offerWaitlistSeats(updated.Id)
+	return s.withAvailability(updated), nil
+}
+
+func (s *TrainServer) ListDepartures(req *trainService.ListDeparturesRequest, stream trainService.TrainService_ListDeparturesServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+
+	s.mu.RLock()
+	var departures []*trainService.Departure
+	for _, departure := range s.store.Departures() {
+		if req.TrainNumber != "" && departure.TrainNumber != req.TrainNumber {
+			continue
+		}
+		if req.Date != "" && departure.Date != req.Date {
+			continue
+		}
+		departures = append(departures, s.withAvailability(departure))
+	}
+	s.mu.RUnlock()
+
+	// Dates and times are zero padded, so they sort as strings.
+	sort.SliceStable(departures, func(i, j int) bool {
+		if departures[i].Date != departures[j].Date {
+			return departures[i].Date < departures[j].Date
+		}
+		return departures[i].DepartureTime < departures[j].DepartureTime
+	})
+	for _, departure := range departures {
+		if err := stream.Send(departure); err != nil {
+			return err
+		}
+	}
+	return nil
+}
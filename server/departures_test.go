@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type departureStream struct {
+	grpc.ServerStream
+	data []*trainService.Departure
+}
+
+func (s *departureStream) Send(departure *trainService.Departure) error {
+	s.data = append(s.data, departure)
+	return nil
+}
+
+type routeStream struct {
+	grpc.ServerStream
+	data []*trainService.Route
+}
+
+func (s *routeStream) Send(route *trainService.Route) error {
+	s.data = append(s.data, route)
+	return nil
+}
+
+// newRouteStore returns a store with the stations LON, LIL and PAR and the
+// route LON-PAR calling at all three, 270 km from LON to LIL and 225 km from
+// LIL to PAR.
+func newRouteStore() *memoryStore {
+	store := newMemoryStore()
+	for _, station := range []*trainService.Station{
+		{Code: "LON", Name: "London"},
+		{Code: "LIL", Name: "Lille"},
+		{Code: "PAR", Name: "Paris"},
+	} {
+		store.AddStation(station)
+	}
+	store.AddRoute(&trainService.Route{Id: "LON-PAR", Stops: []string{"LON", "LIL", "PAR"}, DistancesKm: []int32{270, 225}})
+	return store
+}
+
+func testSchedule(train, date, departureTime string) *trainService.Departure {
+	return &trainService.Departure{
+		TrainNumber:   train,
+		Date:          date,
+		DepartureTime: departureTime,
+		RouteId:       "LON-PAR",
+		Sections: []*trainService.SectionCapacity{
+			{Section: "A", Rows: 1, SeatsPerRow: 2},
+			{Section: "B", Rows: 2, SeatsPerRow: 2},
+		},
+	}
+}
+
+// createDeparture creates the departure scheduled by req, failing the test if
+// it cannot.
+func createDeparture(t *testing.T, server *TrainServer, req *trainService.Departure) *trainService.Departure {
+	t.Helper()
+	departure, err := server.CreateDeparture(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateDeparture failed: %v", err)
+	}
+	return departure
+}
+
+// testJourney returns a request for a ticket for email in section of
+// departure, travelling from LON to PAR.
+func testJourney(email, departure, section string) *trainService.Ticket {
+	ticket := testTicket(email, section)
+	ticket.BookingReference = ""
+	ticket.DepartureId, ticket.From, ticket.To = departure, "LON", "PAR"
+	return ticket
+}
+
+func TestCreateDeparture(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *trainService.Departure
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Valid departure",
+			request:      testSchedule("IC101", "2024-03-01", "09:30"),
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Duplicate departure",
+			request:      testSchedule("IC101", "2024-03-01", "09:30"),
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "Nil request",
+			request:      nil,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Missing train number",
+			request:      testSchedule("", "2024-03-01", "09:30"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid date",
+			request:      testSchedule("IC101", "01/03/2024", "09:30"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid time",
+			request:      testSchedule("IC101", "2024-03-01", "9.30am"),
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown route",
+			request: &trainService.Departure{TrainNumber: "IC101", Date: "2024-03-01", DepartureTime: "10:30", RouteId: "LON-BRU",
+				Sections: []*trainService.SectionCapacity{{Section: "A", Rows: 1, SeatsPerRow: 1}}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "No sections",
+			request:      &trainService.Departure{TrainNumber: "IC101", Date: "2024-03-01", DepartureTime: "10:30", RouteId: "LON-PAR"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Duplicate section",
+			request: &trainService.Departure{TrainNumber: "IC101", Date: "2024-03-01", DepartureTime: "10:30", RouteId: "LON-PAR",
+				Sections: []*trainService.SectionCapacity{
+					{Section: "A", Rows: 1, SeatsPerRow: 1},
+					{Section: "A", Rows: 1, SeatsPerRow: 1},
+				}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Overbooking above 100%",
+			request: &trainService.Departure{TrainNumber: "IC101", Date: "2024-03-01", DepartureTime: "10:30", RouteId: "LON-PAR",
+				Sections: []*trainService.SectionCapacity{{Section: "A", Rows: 1, SeatsPerRow: 1, OverbookingPercent: 150}}},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Empty section",
+			request: &trainService.Departure{TrainNumber: "IC101", Date: "2024-03-01", DepartureTime: "10:30", RouteId: "LON-PAR",
+				Sections: []*trainService.SectionCapacity{{Section: "A", Rows: 0, SeatsPerRow: 4}}},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	server := &TrainServer{store: newRouteStore()}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			departure, err := server.CreateDeparture(context.Background(), tc.request)
+
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if departure.Id != "IC101-20240301-0930" {
+				t.Errorf("Expected departure ID IC101-20240301-0930, got %s", departure.Id)
+			}
+			if departure.AvailableSeats["A"] != 2 || departure.AvailableSeats["B"] != 4 {
+				t.Errorf("Expected 2 seats in A and 4 in B, got %v", departure.AvailableSeats)
+			}
+			if !reflect.DeepEqual(departure.Stops, []string{"LON", "LIL", "PAR"}) {
+				t.Errorf("Expected the stops of route LON-PAR, got %v", departure.Stops)
+			}
+		})
+	}
+}
+
+func TestDepartureBookings(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	var ids []string
+	for _, schedule := range []*trainService.Departure{
+		testSchedule("IC102", "2024-03-02", "18:00"),
+		testSchedule("IC101", "2024-03-01", "09:30"),
+		testSchedule("IC101", "2024-03-02", "07:15"),
+	} {
+		departure := createDeparture(t, server, schedule)
+		ids = append(ids, departure.Id)
+	}
+
+	purchase := func(email, departure, section string) (*trainService.Ticket, error) {
+		return server.PurchaseTicket(ctx, testJourney(email, departure, section))
+	}
+
+	// Section A holds two seats per departure and counts separately.
+	for _, id := range ids[:2] {
+		for _, email := range []string{"deepak@example.com", "test@example.com"} {
+			ticket, err := purchase(email, id, "A")
+			if err != nil {
+				t.Fatalf("PurchaseTicket on %s failed: %v", id, err)
+			}
+			if ticket.DepartureId != id {
+				t.Errorf("Expected ticket on departure %s, got %s", id, ticket.DepartureId)
+			}
+		}
+	}
+	if _, err := purchase("other@example.com", ids[0], "A"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected section A of %s to be sold out, got %v", ids[0], err)
+	}
+	if _, err := purchase("other@example.com", "IC999-20240301-0930", "A"); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown departure, got %v", err)
+	}
+	if _, err := purchase("other@example.com", ids[0], "C"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an unknown section, got %v", err)
+	}
+
+	users := &mockStream{}
+	if err := server.GetUsersBySection(&trainService.Ticket{DepartureId: ids[1], Section: "A"}, users); err != nil {
+		t.Fatalf("GetUsersBySection failed: %v", err)
+	}
+	if len(users.data) != 2 {
+		t.Errorf("Expected 2 passengers in section A of %s, got %d", ids[1], len(users.data))
+	}
+	for _, ticket := range users.data {
+		if ticket.DepartureId != ids[1] {
+			t.Errorf("Expected only tickets on %s, got %v", ids[1], ticket)
+		}
+	}
+
+	stream := &departureStream{}
+	if err := server.ListDepartures(&trainService.ListDeparturesRequest{TrainNumber: "IC101"}, stream); err != nil {
+		t.Fatalf("ListDepartures failed: %v", err)
+	}
+	if len(stream.data) != 2 || stream.data[0].Id != ids[1] || stream.data[1].Id != ids[2] {
+		t.Fatalf("Expected departures %s and %s in schedule order, got %v", ids[1], ids[2], stream.data)
+	}
+	if stream.data[0].AvailableSeats["A"] != 0 || stream.data[1].AvailableSeats["A"] != 2 {
+		t.Errorf("Unexpected availability: %v and %v", stream.data[0].AvailableSeats, stream.data[1].AvailableSeats)
+	}
+
+	stream = &departureStream{}
+	if err := server.ListDepartures(&trainService.ListDeparturesRequest{Date: "2024-03-02"}, stream); err != nil {
+		t.Fatalf("ListDepartures failed: %v", err)
+	}
+	if len(stream.data) != 2 || stream.data[0].Id != ids[2] || stream.data[1].Id != ids[0] {
+		t.Errorf("Expected departures %s and %s, got %v", ids[2], ids[0], stream.data)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// field names a request field, using the proto path (e.g. "user.email"), and
+// its value for validation.
+type field struct {
+	name  string
+	value string
+}
+
+// withDetails builds a status error carrying the given details. Details that
+// cannot be attached are dropped rather than hiding the error itself.
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if len(details) > 0 {
+		if detailed, err := st.WithDetails(details...); err == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+func nilRequest() error {
+	return status.Error(codes.InvalidArgument, "request is nil")
+}
+
+// invalidField reports a single bad request field.
+func invalidField(name, description string) error {
+	return withDetails(codes.InvalidArgument, description, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: name, Description: description},
+		},
+	})
+}
+
+// requireFields returns an InvalidArgument error with msg listing a field
+// violation for each empty field, or nil when every field is set.
+func requireFields(msg string, fields ...field) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, f := range fields {
+		if f.value == "" {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.name,
+				Description: fmt.Sprintf("%s is required", f.name),
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return withDetails(codes.InvalidArgument, msg, &errdetails.BadRequest{FieldViolations: violations})
+}
+
+func ticketNotFound(email string) error {
+	return withDetails(codes.NotFound, fmt.Sprintf("ticket not found for user with email: %s", email), &errdetails.ResourceInfo{
+		ResourceType: "ticket",
+		Owner:        email,
+	})
+}
+
+func bookingNotFound(reference string) error {
+	return withDetails(codes.NotFound, fmt.Sprintf("booking not found with reference: %s", reference), &errdetails.ResourceInfo{
+		ResourceType: "ticket",
+		ResourceName: reference,
+	})
+}
+
+func sectionSoldOut(section string) error {
+	return withDetails(codes.ResourceExhausted, fmt.Sprintf("no available seats in section %s", section), &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{
+			{Subject: "section:" + section, Description: "no available seats"},
+		},
+	})
+}
+
+// preconditionFailed reports that the booking state does not allow the
+// request, e.g. a seat that is already taken.
+func preconditionFailed(kind, subject, msg string) error {
+	return withDetails(codes.FailedPrecondition, msg, &errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: kind, Subject: subject, Description: msg},
+		},
+	})
+}
+
+func internalError(msg string, err error) error {
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+func departureNotFound(id string) error {
+	return withDetails(codes.NotFound, fmt.Sprintf("departure not found with id: %s", id), &errdetails.ResourceInfo{
+		ResourceType: "departure",
+		ResourceName: id,
+	})
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorCodes(t *testing.T) {
+	store := newTestStore(testLayout, map[string]int{"A": 1, "B": 0})
+	server := &TrainServer{store: store, duplicates: duplicateReject}
+	ctx := context.Background()
+
+	booked, err := server.PurchaseTicket(ctx, testTicket("deepak@example.com", "A"))
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		call           func() error
+		expectedCode   codes.Code
+		expectedFields []string
+	}{
+		{
+			name: "Nil request",
+			call: func() error {
+				_, err := server.PurchaseTicket(ctx, nil)
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Empty station fields",
+			call: func() error {
+				ticket := testTicket("test@example.com", "A")
+				ticket.From, ticket.To = "", ""
+				_, err := server.PurchaseTicket(ctx, ticket)
+				return err
+			},
+			expectedCode:   codes.InvalidArgument,
+			expectedFields: []string{"from", "to"},
+		},
+		{
+			name: "Empty user fields",
+			call: func() error {
+				ticket := testTicket("", "A")
+				ticket.User.LastName = ""
+				_, err := server.PurchaseTicket(ctx, ticket)
+				return err
+			},
+			expectedCode:   codes.InvalidArgument,
+			expectedFields: []string{"user.last_name", "user.email"},
+		},
+		{
+			name: "Unknown section",
+			call: func() error {
+				_, err := server.PurchaseTicket(ctx, testTicket("test@example.com", "C"))
+				return err
+			},
+			expectedCode:   codes.InvalidArgument,
+			expectedFields: []string{"section"},
+		},
+		{
+			name: "Sold out section",
+			call: func() error {
+				_, err := server.PurchaseTicket(ctx, testTicket("test@example.com", "B"))
+				return err
+			},
+			expectedCode: codes.ResourceExhausted,
+		},
+		{
+			name: "Duplicate booking",
+			call: func() error {
+				_, err := server.PurchaseTicket(ctx, testTicket("deepak@example.com", "A"))
+				return err
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "Receipt for unknown user",
+			call: func() error {
+				_, err := server.GetReceipt(ctx, &trainService.User{Email: "test@example.com"})
+				return err
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Cancel without email",
+			call: func() error {
+				_, err := server.CancelTicket(ctx, &trainService.User{})
+				return err
+			},
+			expectedCode:   codes.InvalidArgument,
+			expectedFields: []string{"email"},
+		},
+		{
+			name: "Unknown booking reference",
+			call: func() error {
+				_, err := server.GetBooking(ctx, &trainService.BookingReference{Reference: "NOSUCH"})
+				return err
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Move into a sold out section",
+			call: func() error {
+				_, err := server.ModifyUserSeat(ctx, &trainService.Ticket{BookingReference: booked.BookingReference, Section: "B"})
+				return err
+			},
+			expectedCode: codes.ResourceExhausted,
+		},
+		{
+			name: "Seat outside the seat map",
+			call: func() error {
+				_, err := server.ModifyUserSeat(ctx, &trainService.Ticket{
+					BookingReference: booked.BookingReference,
+					Section:          "A",
+					Seat:             &trainService.Seat{Row: 99, Number: 1},
+				})
+				return err
+			},
+			expectedCode:   codes.InvalidArgument,
+			expectedFields: []string{"seat"},
+		},
+		{
+			name: "Invalid swap consent",
+			call: func() error {
+				store.tickets = append(store.tickets, testTicket("test@example.com", "A"))
+				defer func() { store.tickets = store.tickets[:1] }()
+				_, err := server.SwapSeats(ctx, &trainService.SwapSeatsRequest{
+					First:                  &trainService.User{Email: "deepak@example.com"},
+					FirstConsent:           "forged",
+					Second:                 &trainService.User{Email: "test@example.com"},
+					SecondConsent:          "forged",
+					FirstBookingReference:  booked.BookingReference,
+					SecondBookingReference: "TEST",
+				})
+				return err
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := status.Convert(tc.call())
+
+			if st.Code() != tc.expectedCode {
+				t.Errorf("Expected code %v, got %v: %v", tc.expectedCode, st.Code(), st.Message())
+			}
+			var fields []string
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, violation := range badRequest.FieldViolations {
+						fields = append(fields, violation.Field)
+					}
+				}
+			}
+			if !reflect.DeepEqual(fields, tc.expectedFields) {
+				t.Errorf("Expected field violations %v, got %v", tc.expectedFields, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/protobuf/proto"
+)
+
+// fareTable holds the pricing rules the original fare of a journey is
+// computed from:
+//
+//	(BaseFare + PerKm * distance) * section * class * day * demand * advance
+//
+// kept between MinPrice and MaxPrice and converted to the currency quoted in.
+// The passenger factor of a concession is then applied to the original fare
+// as a discount. Amounts are in the base currency of the exchange rates.
+// Departures without a route have no distance and sell every journey at
+// FlatFare instead of the distance based part. Rules are loaded from JSON
+// with the -pricing flag; see defaultFares for the shape.
+type fareTable struct {
+	BaseFare float64 `json:"base_fare"`
+	PerKm    float64 `json:"per_km"`
+	FlatFare float64 `json:"flat_fare"`
+	// Passenger factors by passenger type name, e.g. "CHILD".
+	Passenger map[string]float64 `json:"passenger"`
+	// Day factors by the weekday of the departure date, e.g. "Friday"; days
+	// not listed are charged at 1.
+	Day map[string]float64 `json:"day"`
+	// Load tiers raise the fare as the section fills up on the leg travelled.
+	// The tier with the highest MinLoad not above the load applies.
+	Load []loadTier `json:"load"`
+	// Advance tiers price by the time left before departure. The tier with
+	// the smallest WithinHours not below the hours left applies.
+	Advance []advanceTier `json:"advance"`
+	// MinPrice and MaxPrice bound the original fare; zero means no bound.
+	MinPrice float64 `json:"min_price"`
+	MaxPrice float64 `json:"max_price"`
+	// Refunds decide what is paid back on cancellation; tickets are refunded
+	// in full before departure if no fees are set.
+	Refunds refundPolicy `json:"refunds"`
+	// Classes are the fare classes sold, by name. A section sells the fare
+	// classes of its seat class, and sells a seat class without a fare class
+	// of the same name at a class factor of 1.
+	Classes map[string]fareClass `json:"classes"`
+	// Compensation is owed to passengers bumped from an overbooked
+	// departure.
+	Compensation compensationPolicy `json:"compensation"`
+}
+
+type fareClass struct {
+	// Cabin is the seat class of the sections selling the fare class.
+	Cabin  string  `json:"cabin"`
+	Factor float64 `json:"factor"`
+	// Tickets can only be upgraded to a fare class of higher rank.
+	Rank int `json:"rank"`
+	// Refunds replace the general refund rules for the class if set.
+	Refunds *refundPolicy `json:"refunds,omitempty"`
+}
+
+type loadTier struct {
+	MinLoad float64 `json:"min_load"` // share of seats sold, from 0 to 1
+	Factor  float64 `json:"factor"`
+}
+
+type advanceTier struct {
+	WithinHours float64 `json:"within_hours"`
+	Factor      float64 `json:"factor"`
+}
+
+var defaultFares = fareTable{
+	BaseFare: 5,
+	PerKm:    0.15,
+	FlatFare: 20,
+	Passenger: map[string]float64{
+		trainService.PassengerType_ADULT.String():    1,
+		trainService.PassengerType_CHILD.String():    0.5,
+		trainService.PassengerType_SENIOR.String():   0.7,
+		trainService.PassengerType_STUDENT.String():  0.75,
+		trainService.PassengerType_RAILCARD.String(): 0.67,
+	},
+	Day: map[string]float64{
+		time.Friday.String(): 1.25,
+		time.Sunday.String(): 1.25,
+	},
+	Load: []loadTier{
+		{MinLoad: 0.5, Factor: 1.1},
+		{MinLoad: 0.75, Factor: 1.25},
+		{MinLoad: 0.9, Factor: 1.5},
+	},
+	Advance: []advanceTier{
+		{WithinHours: 24, Factor: 1.3},
+		{WithinHours: 7 * 24, Factor: 1.15},
+	},
+	Refunds: defaultRefunds,
+	Classes: map[string]fareClass{
+		"saver":    {Cabin: "standard", Factor: 0.8, Rank: 0, Refunds: &refundPolicy{NonRefundable: true}},
+		"standard": {Cabin: "standard", Factor: 1, Rank: 1},
+		"first":    {Cabin: "first", Factor: 1.5, Rank: 2},
+	},
+	Compensation: compensationPolicy{Percent: 50, Minimum: 25},
+}
+
+// loadFareTable reads pricing rules from a JSON file.
+func loadFareTable(path string) (*fareTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t fareTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	classes := make(map[string]fareClass, len(t.Classes))
+	for name, class := range t.Classes {
+		class.Cabin = normaliseSeatClass(class.Cabin)
+		classes[normaliseFareClass(name)] = class
+	}
+	t.Classes = classes
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("invalid pricing rules in %s: %w", path, err)
+	}
+	sort.Slice(t.Load, func(i, j int) bool { return t.Load[i].MinLoad < t.Load[j].MinLoad })
+	sort.Slice(t.Advance, func(i, j int) bool { return t.Advance[i].WithinHours < t.Advance[j].WithinHours })
+	t.Refunds.sortFees()
+	for _, class := range t.Classes {
+		if class.Refunds != nil {
+			class.Refunds.sortFees()
+		}
+	}
+	return &t, nil
+}
+
+func (t *fareTable) validate() error {
+	if t.BaseFare < 0 || t.PerKm < 0 || t.FlatFare < 0 {
+		return fmt.Errorf("fares must not be negative")
+	}
+	if t.MaxPrice > 0 && t.MinPrice > t.MaxPrice {
+		return fmt.Errorf("min_price %v is above max_price %v", t.MinPrice, t.MaxPrice)
+	}
+	for name := range trainService.PassengerType_value {
+		if t.Passenger[name] <= 0 {
+			return fmt.Errorf("passenger type %s has no positive factor", name)
+		}
+	}
+	for _, tier := range t.Load {
+		if tier.MinLoad < 0 || tier.MinLoad > 1 || tier.Factor <= 0 {
+			return fmt.Errorf("load tier %+v needs a load between 0 and 1 and a positive factor", tier)
+		}
+	}
+	for _, tier := range t.Advance {
+		if tier.WithinHours < 0 || tier.Factor <= 0 {
+			return fmt.Errorf("advance tier %+v needs positive hours and factor", tier)
+		}
+	}
+	for name, class := range t.Classes {
+		if name == "" || class.Factor <= 0 {
+			return fmt.Errorf("fare class %q needs a name and a positive factor", name)
+		}
+		if class.Refunds != nil {
+			if err := class.Refunds.validate(); err != nil {
+				return fmt.Errorf("fare class %s: %w", name, err)
+			}
+		}
+	}
+	if err := t.Compensation.validate(); err != nil {
+		return err
+	}
+	return t.Refunds.validate()
+}
+
+func normaliseFareClass(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// fareClass returns the name and rules of the fare class called name as sold
+// in section, the fare class of the section's seat class if name is empty.
+func (t *fareTable) fareClass(name string, section *trainService.SectionCapacity) (string, fareClass, error) {
+	cabin := normaliseSeatClass(section.SeatClass)
+	name = normaliseFareClass(name)
+	if name == "" {
+		name = cabin
+	}
+	class, ok := t.Classes[name]
+	if !ok {
+		if name != cabin {
+			return "", fareClass{}, invalidField("fare_class", fmt.Sprintf("unknown fare class %s", name))
+		}
+		class = fareClass{Cabin: cabin, Factor: 1}
+	}
+	if class.Cabin != cabin {
+		return "", fareClass{}, invalidField("fare_class",
+			fmt.Sprintf("fare class %s is sold in %s sections, section %s is %s", name, class.Cabin, section.Section, cabin))
+	}
+	return name, class, nil
+}
+
+// sellsClassOf reports whether section sells the fare class ticket was bought
+// in, the class of its own section if the ticket names none.
+func (t *fareTable) sellsClassOf(ticket *trainService.Ticket, own, section *trainService.SectionCapacity) bool {
+	name, _, err := t.fareClass(ticket.FareClass, own)
+	if err != nil {
+		return false
+	}
+	_, _, err = t.fareClass(name, section)
+	return err == nil
+}
+
+// checkClassSold checks that section of departure sells the fare class of
+// ticket, which keeps its class when it moves there. Callers must hold s.mu.
+func (s *TrainServer) checkClassSold(departure *trainService.Departure, ticket *trainService.Ticket, section string) error {
+	own, ok := findSection(departure, ticket.Section)
+	if !ok {
+		return internalError("failed to load section", fmt.Errorf("section %s does not exist on departure %s", ticket.Section, departure.Id))
+	}
+	target, ok := findSection(departure, section)
+	if !ok {
+		return internalError("failed to load section", fmt.Errorf("section %s does not exist on departure %s", section, departure.Id))
+	}
+	if !s.fareTable().sellsClassOf(ticket, own, target) {
+		return preconditionFailed("FARE_CLASS_MISMATCH", "section:"+section,
+			fmt.Sprintf("section %s does not sell the fare class of %s, use UpgradeTicket to move there", section, ticket.BookingReference))
+	}
+	return nil
+}
+
+// refundPolicy returns the refund rules of the fare class called name.
+func (t *fareTable) refundPolicy(name string) *refundPolicy {
+	if class, ok := t.Classes[name]; ok && class.Refunds != nil {
+		return class.Refunds
+	}
+	return &t.Refunds
+}
+
+func (t *fareTable) demandFactor(load float64) float64 {
+	factor := 1.0
+	for _, tier := range t.Load {
+		if load >= tier.MinLoad {
+			factor = tier.Factor
+		}
+	}
+	return factor
+}
+
+func (t *fareTable) advanceFactor(hoursLeft float64) float64 {
+	for _, tier := range t.Advance {
+		if hoursLeft <= tier.WithinHours {
+			return tier.Factor
+		}
+	}
+	return 1
+}
+
+// distanceKm returns the length of travelled on departure.
+func distanceKm(departure *trainService.Departure, travelled leg) int32 {
+	var distance int32
+	for i := travelled.first; i < travelled.last && i < len(departure.DistancesKm); i++ {
+		distance += departure.DistancesKm[i]
+	}
+	return distance
+}
+
+// departsAt returns when departure leaves, or false for departures without a
+// schedule.
+func departsAt(departure *trainService.Departure) (time.Time, bool) {
+	at, err := time.Parse(departureDateLayout+" "+departureTimeLayout, departure.Date+" "+departure.DepartureTime)
+	return at, err == nil
+}
+
+// quoteFare prices a journey over travelled in a section and fare class of
+// departure for a passenger in currency, with load the share of the section
+// already sold on that leg. The section must exist on departure and the
+// currency must be supported by rates.
+func (t *fareTable) quoteFare(departure *trainService.Departure, travelled leg, section, class string, passenger trainService.PassengerType, load float64, now time.Time, rates *exchangeRates, currency string) (*trainService.FareQuote, error) {
+	passengerFactor, ok := t.Passenger[passenger.String()]
+	if !ok {
+		return nil, invalidField("passenger_type", fmt.Sprintf("unknown passenger type %v", passenger))
+	}
+	capacity, _ := findSection(departure, section)
+	class, classRules, err := t.fareClass(class, capacity)
+	if err != nil {
+		return nil, err
+	}
+	sectionFactor := 1.0
+	if capacity.FareFactor > 0 {
+		sectionFactor = float64(capacity.FareFactor)
+	}
+	dateFactor, advanceFactor := 1.0, 1.0
+	if at, ok := departsAt(departure); ok {
+		if factor, ok := t.Day[at.Weekday().String()]; ok {
+			dateFactor = factor
+		}
+		advanceFactor = t.advanceFactor(at.Sub(now).Hours())
+	}
+	demandFactor := t.demandFactor(load)
+
+	distance := distanceKm(departure, travelled)
+	base := t.FlatFare
+	if len(departure.Stops) >= 2 {
+		base = t.BaseFare + t.PerKm*float64(distance)
+	}
+	fare := base * sectionFactor * classRules.Factor * dateFactor * demandFactor * advanceFactor
+	if t.MinPrice > 0 {
+		fare = math.Max(fare, t.MinPrice)
+	}
+	if t.MaxPrice > 0 {
+		fare = math.Min(fare, t.MaxPrice)
+	}
+	original := rates.fromBase(fare, currency)
+	price := original
+	var discounts []*trainService.Discount
+	if passengerFactor != 1 {
+		price = &trainService.Money{CurrencyCode: currency, MinorUnits: int64(math.Round(float64(original.MinorUnits) * passengerFactor))}
+		discounts = append(discounts, &trainService.Discount{
+			Code:        passenger.String(),
+			Description: strings.ToLower(passenger.String()) + " concession",
+			Amount:      &trainService.Money{CurrencyCode: currency, MinorUnits: original.MinorUnits - price.MinorUnits},
+		})
+	}
+	return &trainService.FareQuote{
+		Price:           price,
+		OriginalFare:    original,
+		Discounts:       discounts,
+		DistanceKm:      distance,
+		BaseFare:        rates.fromBase(base, currency),
+		SectionFactor:   float32(sectionFactor),
+		FareClass:       class,
+		ClassFactor:     float32(classRules.Factor),
+		PassengerFactor: float32(passengerFactor),
+		DateFactor:      float32(dateFactor),
+		DemandFactor:    float32(demandFactor),
+		AdvanceFactor:   float32(advanceFactor),
+	}, nil
+}
+
+// fareTable returns the pricing rules of the server, defaultFares unless
+// others were loaded.
+func (s *TrainServer) fareTable() *fareTable {
+	if s.fares != nil {
+		return s.fares
+	}
+	return &defaultFares
+}
+
+func (s *TrainServer) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// quote prices a journey at the current demand for its section and leg, in
+// a fare class sold in the section and a supported currency. Callers must
+// hold s.mu.
+func (s *TrainServer) quote(departure *trainService.Departure, travelled leg, section, class string, passenger trainService.PassengerType, currency string) (*trainService.FareQuote, error) {
+	load := 0.0
+	if layout, ok := sectionLayout(departure, section); ok && layout.capacity() > 0 {
+		free := s.legSeats(departure, section, travelled, nil)
+		load = 1 - float64(free)/float64(layout.capacity())
+	}
+	return s.fareTable().quoteFare(departure, travelled, section, class, passenger, load, s.clock(), s.exchangeRates(), currency)
+}
+
+func (s *TrainServer) QuoteFare(ctx context.Context, req *trainService.Ticket) (*trainService.FareQuote, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("(From, To, Section) fields are empty",
+		field{"from", req.From}, field{"to", req.To}, field{"section", req.Section}); err != nil {
+		return nil, err
+	}
+	currency, err := s.exchangeRates().currency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	req = proto.Clone(req).(*trainService.Ticket)
+	req.PromoCode = normalisePromoCode(req.PromoCode)
+	req.Currency = currency
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	departure, err := s.findDeparture(req.DepartureId)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sectionLayout(departure, req.Section); !ok {
+		return nil, invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", req.Section, departure.Id))
+	}
+	travelled, err := validateLeg(departure, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	// Concessions are checked here when the passenger is known, and always on
+	// purchase.
+	if req.User != nil {
+		if err := checkConcession(req, departure, s.clock()); err != nil {
+			return nil, err
+		}
+	}
+	quote, err := s.fare(departure, travelled, &trainService.Ticket{
+		Section:       req.Section,
+		FareClass:     req.FareClass,
+		PassengerType: req.PassengerType,
+		PromoCode:     req.PromoCode,
+		Currency:      req.Currency,
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	s.lockQuote(quote, departure, req)
+	return quote, nil
+}
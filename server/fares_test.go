@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestQuoteFare(t *testing.T) {
+	store := newRouteStore()
+	// A month ahead of the departures, so no advance purchase factor applies.
+	server := &TrainServer{store: store, now: func() time.Time { return time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC) }}
+	ctx := context.Background()
+
+	ids := map[string]string{}
+	for day, date := range map[string]string{"Monday": "2024-03-04", "Friday": "2024-03-01"} {
+		schedule := testSchedule("IC101", date, "09:30")
+		schedule.Sections = []*trainService.SectionCapacity{
+			{Section: "A", Rows: 1, SeatsPerRow: 2, FareFactor: 1.5},
+			{Section: "B", Rows: 2, SeatsPerRow: 2},
+		}
+		departure := createDeparture(t, server, schedule)
+		ids[day] = departure.Id
+	}
+	// The departure with the empty ID has no route.
+	store.AddDeparture(testDeparture("", testLayout))
+
+	tests := []struct {
+		name          string
+		request       *trainService.Ticket
+		dateOfBirth   string
+		expectedPrice int64
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "Adult on the whole route",
+			request:       &trainService.Ticket{DepartureId: ids["Monday"], From: "LON", To: "PAR", Section: "B"},
+			expectedPrice: 7925,
+		},
+		{
+			name:          "Child on the first leg",
+			request:       &trainService.Ticket{DepartureId: ids["Monday"], From: "LON", To: "LIL", Section: "B", PassengerType: trainService.PassengerType_CHILD},
+			dateOfBirth:   "2015-06-01",
+			expectedPrice: 2275,
+		},
+		{
+			name:          "Senior in a dearer section",
+			request:       &trainService.Ticket{DepartureId: ids["Monday"], From: "LIL", To: "PAR", Section: "A", PassengerType: trainService.PassengerType_SENIOR},
+			dateOfBirth:   "1950-06-01",
+			expectedPrice: 4069,
+		},
+		{
+			name:          "Adult on a Friday",
+			request:       &trainService.Ticket{DepartureId: ids["Friday"], From: "LON", To: "LIL", Section: "B"},
+			expectedPrice: 5688,
+		},
+		{
+			name:          "Departure without a route",
+			request:       &trainService.Ticket{From: "London", To: "Paris", Section: "A"},
+			expectedPrice: 2000,
+		},
+		{
+			name:         "Unknown passenger type",
+			request:      &trainService.Ticket{DepartureId: ids["Monday"], From: "LON", To: "PAR", Section: "B", PassengerType: 42},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Stations in the wrong order",
+			request:      &trainService.Ticket{DepartureId: ids["Monday"], From: "PAR", To: "LON", Section: "B"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Nil request",
+			request:      nil,
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := server.QuoteFare(ctx, tc.request)
+
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if !proto.Equal(quote.Price, eur(tc.expectedPrice)) {
+				t.Errorf("Expected price %v, got %v (%v)", tc.expectedPrice, quote.Price, quote)
+			}
+
+			// Purchases are charged the quoted fare whatever the client sends.
+			ticket := testTicket("quote@example.com", tc.request.Section)
+			ticket.DepartureId, ticket.From, ticket.To = tc.request.DepartureId, tc.request.From, tc.request.To
+			ticket.PassengerType = tc.request.PassengerType
+			ticket.User.DateOfBirth = tc.dateOfBirth
+			ticket.Price = eur(1)
+			booked, err := server.PurchaseTicket(ctx, ticket)
+			if err != nil {
+				t.Fatalf("PurchaseTicket failed: %v", err)
+			}
+			if !proto.Equal(booked.Price, quote.Price) || !proto.Equal(booked.OriginalFare, quote.OriginalFare) || len(booked.Discounts) != len(quote.Discounts) {
+				t.Errorf("Expected to be charged %v, got %v", quote, booked)
+			}
+			if _, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: booked.BookingReference}); err != nil {
+				t.Fatalf("CancelBooking failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestDynamicPricing(t *testing.T) {
+	now := time.Date(2024, 3, 3, 21, 30, 0, 0, time.UTC)
+	store := newRouteStore()
+	server := &TrainServer{store: store, duplicates: duplicateAllow, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	// A Monday departure 12 hours away with 4 seats in section B.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	journey := &trainService.Ticket{DepartureId: departure.Id, From: "LON", To: "LIL", Section: "B"}
+	quote := func() *trainService.FareQuote {
+		t.Helper()
+		quote, err := server.QuoteFare(ctx, journey)
+		if err != nil {
+			t.Fatalf("QuoteFare failed: %v", err)
+		}
+		return quote
+	}
+	purchase := func(email, token string) (*trainService.Ticket, error) {
+		ticket := testJourney(email, departure.Id, "B")
+		ticket.To = "LIL"
+		ticket.QuoteToken = token
+		return server.PurchaseTicket(ctx, ticket)
+	}
+
+	// 45.50 base, 1.3 within a day of departure.
+	first := quote()
+	if !proto.Equal(first.Price, eur(5915)) || first.AdvanceFactor != 1.3 || first.DemandFactor != 1 {
+		t.Errorf("Unexpected quote on an empty train: %v", first)
+	}
+
+	// Two of four seats sold puts the section in the 50% load tier. The
+	// earlier quote still holds its price.
+	for _, email := range []string{"one@example.com", "two@example.com"} {
+		if _, err := purchase(email, ""); err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+	}
+	if second := quote(); !proto.Equal(second.Price, eur(6507)) || second.DemandFactor != 1.1 {
+		t.Errorf("Expected the 50%% load tier to apply, got %v", second)
+	}
+	locked, err := purchase("three@example.com", first.QuoteToken)
+	if err != nil {
+		t.Fatalf("PurchaseTicket with a quote token failed: %v", err)
+	}
+	if !proto.Equal(locked.Price, first.Price) || locked.QuoteToken != "" {
+		t.Errorf("Expected the locked price %v without the token stored, got %v", first.Price, locked)
+	}
+
+	// Tokens only hold for the quoted journey and window.
+	tampered := first.QuoteToken[:len(first.QuoteToken)-2] + "AA"
+	if _, err := purchase("four@example.com", tampered); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a tampered token, got %v", err)
+	}
+	ticket := testJourney("four@example.com", departure.Id, "A")
+	ticket.To = "LIL"
+	ticket.QuoteToken = first.QuoteToken
+	if _, err := server.PurchaseTicket(ctx, ticket); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a token quoted for another section, got %v", err)
+	}
+	now = now.Add(defaultQuoteTTL + time.Second)
+	if _, err := purchase("four@example.com", first.QuoteToken); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for an expired token, got %v", err)
+	}
+
+	// Floor and ceiling bound the final price.
+	rules := defaultFares
+	rules.MaxPrice = 60
+	server.fares = &rules
+	if capped := quote(); !proto.Equal(capped.Price, eur(6000)) {
+		t.Errorf("Expected the price capped at 60, got %v", capped.Price)
+	}
+	rules.MaxPrice, rules.MinPrice = 0, 100
+	if floored := quote(); !proto.Equal(floored.Price, eur(10000)) {
+		t.Errorf("Expected the price raised to 100, got %v", floored.Price)
+	}
+}
+
+func TestLoadFareTable(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       string
+		expectedErr bool
+	}{
+		{
+			name: "Valid rules",
+			rules: `{"base_fare": 4, "per_km": 0.2, "flat_fare": 15,
+				"passenger": {"ADULT": 1, "CHILD": 0.5, "SENIOR": 0.6, "STUDENT": 0.8, "RAILCARD": 0.67},
+				"load": [{"min_load": 0.8, "factor": 1.4}, {"min_load": 0.5, "factor": 1.2}],
+				"advance": [{"within_hours": 168, "factor": 1.1}, {"within_hours": 24, "factor": 1.5}],
+				"min_price": 10, "max_price": 200,
+				"refunds": {"fees": [{"within_hours": 24, "percent": 20}, {"within_hours": 1, "percent": 100}]},
+				"classes": {"Saver": {"cabin": "standard", "factor": 0.7, "refunds": {"non_refundable": true}}, "first": {"cabin": "first", "factor": 1.6, "rank": 1}}}`,
+		},
+		{
+			name:        "Missing passenger type",
+			rules:       `{"base_fare": 4, "passenger": {"ADULT": 1}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Floor above ceiling",
+			rules:       `{"passenger": {"ADULT": 1, "CHILD": 0.5, "SENIOR": 0.6, "STUDENT": 0.8, "RAILCARD": 0.67}, "min_price": 50, "max_price": 20}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Load above 100%",
+			rules:       `{"passenger": {"ADULT": 1, "CHILD": 0.5, "SENIOR": 0.6, "STUDENT": 0.8, "RAILCARD": 0.67}, "load": [{"min_load": 1.5, "factor": 2}]}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Fee above 100%",
+			rules:       `{"passenger": {"ADULT": 1, "CHILD": 0.5, "SENIOR": 0.6, "STUDENT": 0.8, "RAILCARD": 0.67}, "refunds": {"fees": [{"within_hours": 2, "percent": 150}]}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Fare class without factor",
+			rules:       `{"passenger": {"ADULT": 1, "CHILD": 0.5, "SENIOR": 0.6, "STUDENT": 0.8, "RAILCARD": 0.67}, "classes": {"first": {"cabin": "first"}}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Negative compensation",
+			rules:       `{"passenger": {"ADULT": 1, "CHILD": 0.5, "SENIOR": 0.6, "STUDENT": 0.8, "RAILCARD": 0.67}, "compensation": {"percent": -10}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Not JSON",
+			rules:       `base_fare = 4`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pricing.json")
+			if err := os.WriteFile(path, []byte(tc.rules), 0o644); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			rules, err := loadFareTable(path)
+
+			if tc.expectedErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			// Tiers are sorted so the lookups can scan them in order.
+			if rules.demandFactor(0.9) != 1.4 || rules.demandFactor(0.6) != 1.2 || rules.demandFactor(0.1) != 1 {
+				t.Errorf("Unexpected load tiers: %+v", rules.Load)
+			}
+			if rules.advanceFactor(12) != 1.5 || rules.advanceFactor(48) != 1.1 || rules.advanceFactor(500) != 1 {
+				t.Errorf("Unexpected advance tiers: %+v", rules.Advance)
+			}
+			if fees := rules.Refunds.Fees; fees[0].WithinHours != 1 || fees[1].WithinHours != 24 {
+				t.Errorf("Unexpected fee tiers: %+v", fees)
+			}
+		})
+	}
+}
@@ -0,0 +1,848 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	walFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+
+	// defaultSnapshotEvery is how many log records are written before the log
+	// is compacted into a new snapshot.
+	defaultSnapshotEvery = 100
+)
+
+// Write-ahead log operations, one per mutating BookingStore method.
+const (
+	opAddStation    = "add_station"
+	opAddRoute      = "add_route"
+	opAddDeparture  = "add_departure"
+	opAddPromoCode  = "add_promo_code"
+	opAddTicket     = "add_ticket"
+	opRemoveTicket  = "remove_ticket"
+	opUpdateTicket  = "update_ticket"
+	opMoveTicket    = "move_ticket"
+	opReplaceTicket = "replace_ticket"
+	opSwapSeats     = "swap_seats"
+	opAddHold       = "add_hold"
+	opRemoveHold    = "remove_hold"
+	opConfirmHold   = "confirm_hold"
+	opUpdateHold    = "update_hold"
+
+	opUpdateDeparture      = "update_departure"
+	opAddWaitlistEntry     = "add_waitlist_entry"
+	opRemoveWaitlistEntry  = "remove_waitlist_entry"
+	opUpdateWaitlistEntry  = "update_waitlist_entry"
+	opAddHolds             = "add_holds"
+	opConfirmHolds         = "confirm_holds"
+	opSettleDeparture      = "settle_departure"
+	opAddPendingUpgrade    = "add_pending_upgrade"
+	opRemovePendingUpgrade = "remove_pending_upgrade"
+)
+
+// fileStore keeps the bookings in memory and makes every change durable in a
+// directory holding a write-ahead log and a snapshot of the state.
+//
+// A change is appended to the log and synced before it is applied in memory,
+// and each change is a single record, so a crash either keeps the whole
+// change (e.g. both the ticket and the seat it took) or none of it. Every
+// snapshotEvery records the state is written to a new snapshot and the log
+// is truncated. On open the snapshot is loaded and the log replayed on top.
+type fileStore struct {
+	dir           string
+	snapshotEvery int
+
+	mem     *memoryStore
+	wal     *os.File
+	walSize int64  // length of the log up to the last complete record
+	seq     uint64 // sequence number of the last record applied
+	pending int    // records written since the last snapshot
+}
+
+// walRecord is one line of the write-ahead log.
+type walRecord struct {
+	Seq            uint64          `json:"seq"`
+	Op             string          `json:"op"`
+	Reference      string          `json:"reference,omitempty"`
+	OtherReference string          `json:"other_reference,omitempty"`
+	Ticket         json.RawMessage `json:"ticket,omitempty"`
+	Departure      json.RawMessage `json:"departure,omitempty"`
+	Station        json.RawMessage `json:"station,omitempty"`
+	Route          json.RawMessage `json:"route,omitempty"`
+	PromoCode      json.RawMessage `json:"promo_code,omitempty"`
+	Hold           json.RawMessage `json:"hold,omitempty"`
+	WaitlistEntry  json.RawMessage `json:"waitlist_entry,omitempty"`
+	Upgrade        json.RawMessage `json:"upgrade,omitempty"`
+	// Batched operations carry one element per hold or ticket.
+	References []string          `json:"references,omitempty"`
+	Tickets    []json.RawMessage `json:"tickets,omitempty"`
+	Holds      []json.RawMessage `json:"holds,omitempty"`
+}
+
+type snapshot struct {
+	Seq        uint64                      `json:"seq"`
+	SeatCount  map[string]map[string][]int `json:"seat_count"`
+	Stations   []json.RawMessage           `json:"stations"`
+	Routes     []json.RawMessage           `json:"routes"`
+	Departures []json.RawMessage           `json:"departures"`
+	PromoCodes []json.RawMessage           `json:"promo_codes"`
+	Tickets    []json.RawMessage           `json:"tickets"`
+	Holds      []json.RawMessage           `json:"holds"`
+	Upgrades   []json.RawMessage           `json:"upgrades"`
+	Waitlist   []json.RawMessage           `json:"waitlist"`
+}
+
+// openFileStore loads the bookings saved in dir, creating it if needed.
+func openFileStore(dir string) (*fileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f := &fileStore{
+		dir:           dir,
+		snapshotEvery: defaultSnapshotEvery,
+		mem:           newMemoryStore(),
+	}
+	if err := f.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if err := f.replay(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *fileStore) Stations() []*trainService.Station {
+	return f.mem.Stations()
+}
+
+func (f *fileStore) AddStation(station *trainService.Station) error {
+	for _, existing := range f.mem.stations {
+		if existing.Code == station.Code {
+			return errStationExists
+		}
+	}
+	raw, err := protojson.Marshal(station)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddStation, Station: raw}, nil)
+}
+
+func (f *fileStore) Routes() []*trainService.Route {
+	return f.mem.Routes()
+}
+
+func (f *fileStore) FindRoute(id string) (*trainService.Route, error) {
+	return f.mem.FindRoute(id)
+}
+
+func (f *fileStore) AddRoute(route *trainService.Route) error {
+	if _, err := f.mem.FindRoute(route.Id); err == nil {
+		return errRouteExists
+	}
+	raw, err := protojson.Marshal(route)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddRoute, Route: raw}, nil)
+}
+
+func (f *fileStore) Departures() []*trainService.Departure {
+	return f.mem.Departures()
+}
+
+func (f *fileStore) FindDeparture(id string) (*trainService.Departure, error) {
+	return f.mem.FindDeparture(id)
+}
+
+func (f *fileStore) AddDeparture(departure *trainService.Departure) error {
+	if _, err := f.mem.FindDeparture(departure.Id); err == nil {
+		return errDepartureExists
+	}
+	raw, err := protojson.Marshal(departure)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddDeparture, Departure: raw}, nil)
+}
+
+func (f *fileStore) UpdateDeparture(departure *trainService.Departure) error {
+	if _, err := f.mem.FindDeparture(departure.Id); err != nil {
+		return err
+	}
+	raw, err := protojson.Marshal(departure)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opUpdateDeparture, Departure: raw}, nil)
+}
+
+func (f *fileStore) PromoCodes() []*trainService.PromoCode {
+	return f.mem.PromoCodes()
+}
+
+func (f *fileStore) FindPromoCode(code string) (*trainService.PromoCode, error) {
+	return f.mem.FindPromoCode(code)
+}
+
+func (f *fileStore) AddPromoCode(promo *trainService.PromoCode) error {
+	if _, err := f.mem.FindPromoCode(promo.Code); err == nil {
+		return errPromoCodeExists
+	}
+	raw, err := protojson.Marshal(promo)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddPromoCode, PromoCode: raw}, nil)
+}
+
+func (f *fileStore) Tickets() []*trainService.Ticket {
+	return f.mem.Tickets()
+}
+
+func (f *fileStore) FindTicket(reference string) (*trainService.Ticket, error) {
+	return f.mem.FindTicket(reference)
+}
+
+func (f *fileStore) SeatCount(departure, section string) int {
+	return f.mem.SeatCount(departure, section)
+}
+
+func (f *fileStore) SegmentSeatCounts(departure, section string) []int {
+	return f.mem.SegmentSeatCounts(departure, section)
+}
+
+func (f *fileStore) AddTicket(ticket *trainService.Ticket) error {
+	if _, err := f.mem.legOf(ticket); err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddTicket}, ticket)
+}
+
+func (f *fileStore) RemoveTicket(reference string) (*trainService.Ticket, error) {
+	ticket, err := f.mem.FindTicket(reference)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.commit(walRecord{Op: opRemoveTicket, Reference: reference}, nil); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (f *fileStore) UpdateTicket(ticket *trainService.Ticket) error {
+	if _, err := f.mem.FindTicket(ticket.BookingReference); err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opUpdateTicket}, ticket)
+}
+
+func (f *fileStore) MoveTicket(ticket *trainService.Ticket) error {
+	if _, err := f.mem.FindTicket(ticket.BookingReference); err != nil {
+		return err
+	}
+	if _, err := f.mem.legOf(ticket); err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opMoveTicket}, ticket)
+}
+
+func (f *fileStore) ReplaceTicket(reference string, ticket *trainService.Ticket) error {
+	if _, err := f.mem.FindTicket(reference); err != nil {
+		return err
+	}
+	if _, err := f.mem.legOf(ticket); err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opReplaceTicket, Reference: reference}, ticket)
+}
+
+func (f *fileStore) SwapSeats(reference, otherReference string) error {
+	if _, err := f.mem.FindTicket(reference); err != nil {
+		return err
+	}
+	if _, err := f.mem.FindTicket(otherReference); err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opSwapSeats, Reference: reference, OtherReference: otherReference}, nil)
+}
+
+func (f *fileStore) Holds() []*trainService.SeatHold {
+	return f.mem.Holds()
+}
+
+func (f *fileStore) FindHold(token string) (*trainService.SeatHold, error) {
+	return f.mem.FindHold(token)
+}
+
+func (f *fileStore) AddHold(hold *trainService.SeatHold) error {
+	if _, err := f.mem.legOf(hold.Ticket); err != nil {
+		return err
+	}
+	raw, err := protojson.Marshal(hold)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddHold, Hold: raw}, nil)
+}
+
+func (f *fileStore) RemoveHold(token string) (*trainService.SeatHold, error) {
+	hold, err := f.mem.FindHold(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.commit(walRecord{Op: opRemoveHold, Reference: token}, nil); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (f *fileStore) UpdateHold(hold *trainService.SeatHold) error {
+	if _, err := f.mem.FindHold(hold.Token); err != nil {
+		return err
+	}
+	raw, err := protojson.Marshal(hold)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opUpdateHold, Hold: raw}, nil)
+}
+
+func (f *fileStore) ConfirmHold(token, replaced string, ticket *trainService.Ticket) error {
+	if _, err := f.mem.FindHold(token); err != nil {
+		return err
+	}
+	if replaced != "" {
+		if _, err := f.mem.FindTicket(replaced); err != nil {
+			return err
+		}
+	}
+	if _, err := f.mem.legOf(ticket); err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opConfirmHold, Reference: token, OtherReference: replaced}, ticket)
+}
+
+func (f *fileStore) AddHolds(holds []*trainService.SeatHold) error {
+	for _, hold := range holds {
+		if _, err := f.mem.legOf(hold.Ticket); err != nil {
+			return err
+		}
+	}
+	raw, err := marshalAll(holds)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddHolds, Holds: raw}, nil)
+}
+
+func (f *fileStore) ConfirmHolds(tokens []string, tickets []*trainService.Ticket) error {
+	if err := f.mem.checkConfirmHolds(tokens, tickets); err != nil {
+		return err
+	}
+	raw, err := marshalAll(tickets)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opConfirmHolds, References: tokens, Tickets: raw}, nil)
+}
+
+func (f *fileStore) SettleDeparture(departure *trainService.Departure, tickets []*trainService.Ticket, cancelled []string) error {
+	if err := f.mem.checkSettleDeparture(departure, tickets, cancelled); err != nil {
+		return err
+	}
+	rawDeparture, err := protojson.Marshal(departure)
+	if err != nil {
+		return err
+	}
+	raw, err := marshalAll(tickets)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opSettleDeparture, Departure: rawDeparture, Tickets: raw, References: cancelled}, nil)
+}
+
+func (f *fileStore) PendingUpgrades() []*trainService.PendingUpgrade {
+	return f.mem.PendingUpgrades()
+}
+
+func (f *fileStore) AddPendingUpgrade(upgrade *trainService.PendingUpgrade) error {
+	raw, err := protojson.Marshal(upgrade)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddPendingUpgrade, Upgrade: raw}, nil)
+}
+
+func (f *fileStore) RemovePendingUpgrade(key string) error {
+	for _, upgrade := range f.mem.upgrades {
+		if upgrade.ChargeKey == key {
+			return f.commit(walRecord{Op: opRemovePendingUpgrade, Reference: key}, nil)
+		}
+	}
+	return errUpgradeNotFound
+}
+
+func (f *fileStore) Waitlist() []*trainService.WaitlistEntry {
+	return f.mem.Waitlist()
+}
+
+func (f *fileStore) FindWaitlistEntry(id string) (*trainService.WaitlistEntry, error) {
+	return f.mem.FindWaitlistEntry(id)
+}
+
+func (f *fileStore) AddWaitlistEntry(entry *trainService.WaitlistEntry) error {
+	raw, err := protojson.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opAddWaitlistEntry, WaitlistEntry: raw}, nil)
+}
+
+func (f *fileStore) RemoveWaitlistEntry(id string) (*trainService.WaitlistEntry, error) {
+	entry, err := f.mem.FindWaitlistEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.commit(walRecord{Op: opRemoveWaitlistEntry, Reference: id}, nil); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (f *fileStore) UpdateWaitlistEntry(entry *trainService.WaitlistEntry) error {
+	if _, err := f.mem.FindWaitlistEntry(entry.Id); err != nil {
+		return err
+	}
+	raw, err := protojson.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return f.commit(walRecord{Op: opUpdateWaitlistEntry, WaitlistEntry: raw}, nil)
+}
+
+// Close writes a final snapshot and closes the log.
+func (f *fileStore) Close() error {
+	if f.pending > 0 {
+		if err := f.compact(); err != nil {
+			return err
+		}
+	}
+	return f.wal.Close()
+}
+
+// commit logs rec, with ticket attached if given, and applies it in memory.
+// Callers check that rec can be applied before committing it.
+func (f *fileStore) commit(rec walRecord, ticket *trainService.Ticket) error {
+	rec.Seq = f.seq + 1
+	if ticket != nil {
+		raw, err := protojson.Marshal(ticket)
+		if err != nil {
+			return err
+		}
+		rec.Ticket = raw
+	}
+	line, err := encodeRecord(rec)
+	if err != nil {
+		return err
+	}
+	if err := f.appendLine(line); err != nil {
+		return err
+	}
+	if err := f.apply(rec); err != nil {
+		return err
+	}
+	f.pending++
+	if f.pending >= f.snapshotEvery {
+		// The change is already durable in the log, so a failed compaction
+		// is only logged and retried after the next record.
+		if err := f.compact(); err != nil {
+			log.Printf("failed to compact booking log: %v", err)
+		}
+	}
+	return nil
+}
+
+// appendLine writes line to the end of the log and syncs it. If that fails the
+// log is cut back so later records are not stuck behind a broken one.
+func (f *fileStore) appendLine(line []byte) error {
+	_, err := f.wal.WriteAt(line, f.walSize)
+	if err == nil {
+		err = f.wal.Sync()
+	}
+	if err != nil {
+		if truncErr := f.wal.Truncate(f.walSize); truncErr != nil {
+			return fmt.Errorf("%w (and failed to roll back the log: %v)", err, truncErr)
+		}
+		return err
+	}
+	f.walSize += int64(len(line))
+	return nil
+}
+
+func (f *fileStore) apply(rec walRecord) error {
+	var ticket *trainService.Ticket
+	if rec.Ticket != nil {
+		ticket = &trainService.Ticket{}
+		if err := protojson.Unmarshal(rec.Ticket, ticket); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch rec.Op {
+	case opAddStation:
+		station := &trainService.Station{}
+		if err := protojson.Unmarshal(rec.Station, station); err != nil {
+			return err
+		}
+		err = f.mem.AddStation(station)
+	case opAddRoute:
+		route := &trainService.Route{}
+		if err := protojson.Unmarshal(rec.Route, route); err != nil {
+			return err
+		}
+		err = f.mem.AddRoute(route)
+	case opAddDeparture:
+		departure := &trainService.Departure{}
+		if err := protojson.Unmarshal(rec.Departure, departure); err != nil {
+			return err
+		}
+		err = f.mem.AddDeparture(departure)
+	case opAddPromoCode:
+		promo := &trainService.PromoCode{}
+		if err := protojson.Unmarshal(rec.PromoCode, promo); err != nil {
+			return err
+		}
+		err = f.mem.AddPromoCode(promo)
+	case opAddTicket:
+		err = f.mem.AddTicket(ticket)
+	case opRemoveTicket:
+		_, err = f.mem.RemoveTicket(rec.Reference)
+	case opUpdateTicket:
+		err = f.mem.UpdateTicket(ticket)
+	case opMoveTicket:
+		err = f.mem.MoveTicket(ticket)
+	case opReplaceTicket:
+		err = f.mem.ReplaceTicket(rec.Reference, ticket)
+	case opSwapSeats:
+		err = f.mem.SwapSeats(rec.Reference, rec.OtherReference)
+	case opAddHold, opUpdateHold:
+		hold := &trainService.SeatHold{}
+		if err := protojson.Unmarshal(rec.Hold, hold); err != nil {
+			return err
+		}
+		if rec.Op == opAddHold {
+			err = f.mem.AddHold(hold)
+		} else {
+			err = f.mem.UpdateHold(hold)
+		}
+	case opRemoveHold:
+		_, err = f.mem.RemoveHold(rec.Reference)
+	case opConfirmHold:
+		err = f.mem.ConfirmHold(rec.Reference, rec.OtherReference, ticket)
+	case opUpdateDeparture:
+		departure := &trainService.Departure{}
+		if err := protojson.Unmarshal(rec.Departure, departure); err != nil {
+			return err
+		}
+		err = f.mem.UpdateDeparture(departure)
+	case opAddWaitlistEntry, opUpdateWaitlistEntry:
+		entry := &trainService.WaitlistEntry{}
+		if err := protojson.Unmarshal(rec.WaitlistEntry, entry); err != nil {
+			return err
+		}
+		if rec.Op == opAddWaitlistEntry {
+			err = f.mem.AddWaitlistEntry(entry)
+		} else {
+			err = f.mem.UpdateWaitlistEntry(entry)
+		}
+	case opRemoveWaitlistEntry:
+		_, err = f.mem.RemoveWaitlistEntry(rec.Reference)
+	case opAddHolds:
+		var holds []*trainService.SeatHold
+		if holds, err = unmarshalAll[trainService.SeatHold](rec.Holds); err == nil {
+			err = f.mem.AddHolds(holds)
+		}
+	case opConfirmHolds:
+		var tickets []*trainService.Ticket
+		if tickets, err = unmarshalAll[trainService.Ticket](rec.Tickets); err == nil {
+			err = f.mem.ConfirmHolds(rec.References, tickets)
+		}
+	case opSettleDeparture:
+		departure := &trainService.Departure{}
+		if err := protojson.Unmarshal(rec.Departure, departure); err != nil {
+			return err
+		}
+		var tickets []*trainService.Ticket
+		if tickets, err = unmarshalAll[trainService.Ticket](rec.Tickets); err == nil {
+			err = f.mem.SettleDeparture(departure, tickets, rec.References)
+		}
+	case opAddPendingUpgrade:
+		upgrade := &trainService.PendingUpgrade{}
+		if err := protojson.Unmarshal(rec.Upgrade, upgrade); err != nil {
+			return err
+		}
+		err = f.mem.AddPendingUpgrade(upgrade)
+	case opRemovePendingUpgrade:
+		err = f.mem.RemovePendingUpgrade(rec.Reference)
+	default:
+		err = fmt.Errorf("unknown operation %q", rec.Op)
+	}
+	if err != nil {
+		return err
+	}
+	f.seq = rec.Seq
+	return nil
+}
+
+// compact writes the current state to a new snapshot and starts an empty log.
+// Records already in the snapshot are skipped on replay, so a crash between
+// the two steps is harmless.
+func (f *fileStore) compact() error {
+	snap := snapshot{Seq: f.seq, SeatCount: f.mem.seatCount}
+	var err error
+	if snap.Stations, err = marshalAll(f.mem.stations); err != nil {
+		return err
+	}
+	if snap.Routes, err = marshalAll(f.mem.routes); err != nil {
+		return err
+	}
+	if snap.Departures, err = marshalAll(f.mem.departures); err != nil {
+		return err
+	}
+	if snap.PromoCodes, err = marshalAll(f.mem.promoCodes); err != nil {
+		return err
+	}
+	if snap.Tickets, err = marshalAll(f.mem.tickets); err != nil {
+		return err
+	}
+	if snap.Holds, err = marshalAll(f.mem.holds); err != nil {
+		return err
+	}
+	if snap.Upgrades, err = marshalAll(f.mem.upgrades); err != nil {
+		return err
+	}
+	if snap.Waitlist, err = marshalAll(f.mem.waitlist); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(f.dir, snapshotFileName), data); err != nil {
+		return err
+	}
+
+	// The old log is only let go once the empty one has replaced it. Until
+	// then records keep going to the old log, which is still replayed on top
+	// of the snapshot if the store restarts.
+	wal, err := createEmptyFile(filepath.Join(f.dir, walFileName))
+	if err != nil {
+		return err
+	}
+	if err := f.wal.Close(); err != nil {
+		log.Printf("failed to close compacted booking log: %v", err)
+	}
+	f.wal = wal
+	f.walSize = 0
+	f.pending = 0
+	return nil
+}
+
+func (f *fileStore) loadSnapshot() error {
+	path := filepath.Join(f.dir, snapshotFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	f.seq = snap.Seq
+	f.mem.seatCount = snap.SeatCount
+	if f.mem.stations, err = unmarshalAll[trainService.Station](snap.Stations); err != nil {
+		return fmt.Errorf("failed to decode station in %s: %w", path, err)
+	}
+	if f.mem.routes, err = unmarshalAll[trainService.Route](snap.Routes); err != nil {
+		return fmt.Errorf("failed to decode route in %s: %w", path, err)
+	}
+	if f.mem.departures, err = unmarshalAll[trainService.Departure](snap.Departures); err != nil {
+		return fmt.Errorf("failed to decode departure in %s: %w", path, err)
+	}
+	if f.mem.promoCodes, err = unmarshalAll[trainService.PromoCode](snap.PromoCodes); err != nil {
+		return fmt.Errorf("failed to decode promo code in %s: %w", path, err)
+	}
+	if f.mem.tickets, err = unmarshalAll[trainService.Ticket](snap.Tickets); err != nil {
+		return fmt.Errorf("failed to decode ticket in %s: %w", path, err)
+	}
+	if f.mem.holds, err = unmarshalAll[trainService.SeatHold](snap.Holds); err != nil {
+		return fmt.Errorf("failed to decode seat hold in %s: %w", path, err)
+	}
+	if f.mem.upgrades, err = unmarshalAll[trainService.PendingUpgrade](snap.Upgrades); err != nil {
+		return fmt.Errorf("failed to decode pending upgrade in %s: %w", path, err)
+	}
+	if f.mem.waitlist, err = unmarshalAll[trainService.WaitlistEntry](snap.Waitlist); err != nil {
+		return fmt.Errorf("failed to decode waitlist entry in %s: %w", path, err)
+	}
+	return nil
+}
+
+func marshalAll[M proto.Message](messages []M) ([]json.RawMessage, error) {
+	raws := make([]json.RawMessage, 0, len(messages))
+	for _, message := range messages {
+		raw, err := protojson.Marshal(message)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+	return raws, nil
+}
+
+// unmarshalAll decodes raws into new messages of type T.
+func unmarshalAll[T any, M interface {
+	*T
+	proto.Message
+}](raws []json.RawMessage) ([]M, error) {
+	messages := make([]M, 0, len(raws))
+	for _, raw := range raws {
+		message := M(new(T))
+		if err := protojson.Unmarshal(raw, message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// replay applies the log records newer than the snapshot. A record that was
+// only partly written when the process died is cut off the end of the log.
+func (f *fileStore) replay() error {
+	path := filepath.Join(f.dir, walFileName)
+	wal, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+
+	var valid int64
+	reader := bufio.NewReader(wal)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			wal.Close()
+			return err
+		}
+		rec, ok := decodeRecord(line)
+		if !ok {
+			break
+		}
+		valid += int64(len(line))
+		if rec.Seq <= f.seq {
+			continue
+		}
+		if err := f.apply(rec); err != nil {
+			wal.Close()
+			return fmt.Errorf("failed to replay record %d: %w", rec.Seq, err)
+		}
+		f.pending++
+	}
+
+	if err := wal.Truncate(valid); err != nil {
+		wal.Close()
+		return err
+	}
+	f.wal = wal
+	f.walSize = valid
+	return nil
+}
+
+// encodeRecord renders rec as a log line prefixed with its CRC-32 checksum.
+func encodeRecord(rec walRecord) ([]byte, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprintf("%08x %s\n", crc32.ChecksumIEEE(data), data)), nil
+}
+
+// decodeRecord parses a log line, reporting false for a torn or corrupt one.
+func decodeRecord(line []byte) (walRecord, bool) {
+	var rec walRecord
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	sum, data, found := bytes.Cut(line, []byte(" "))
+	if !found {
+		return rec, false
+	}
+	var checksum uint32
+	if _, err := fmt.Sscanf(string(sum), "%08x", &checksum); err != nil || checksum != crc32.ChecksumIEEE(data) {
+		return rec, false
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, false
+	}
+	return rec, true
+}
+
+// writeFileAtomic replaces path with data so that readers see either the old
+// or the new contents, never a mix.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// createEmptyFile atomically replaces the file at path with an empty one and
+// returns it open for reading and writing.
+func createEmptyFile(path string) (*os.File, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	err = tmp.Sync()
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return tmp, nil
+}
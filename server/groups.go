@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxGroupSize is the most passengers a group booking can have.
+const maxGroupSize = 50
+
+func groupSoldOut(section string, free, passengers int) error {
+	return withDetails(codes.ResourceExhausted,
+		fmt.Sprintf("section %s has %d seats available, not enough for %d passengers", section, free, passengers),
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{
+				{Subject: "section:" + section, Description: "not enough available seats for the group"},
+			},
+		})
+}
+
+// groupTickets returns the booked tickets of the group booking with the given
+// reference in booking order. Callers must hold s.mu.
+func (s *TrainServer) groupTickets(reference string) []*trainService.Ticket {
+	var tickets []*trainService.Ticket
+	for _, ticket := range s.store.Tickets() {
+		if ticket.GroupReference == reference {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// totalPrice adds up the prices of tickets, which share a currency.
+func totalPrice(tickets []*trainService.Ticket) *trainService.Money {
+	total := &trainService.Money{CurrencyCode: tickets[0].Price.CurrencyCode}
+	for _, ticket := range tickets {
+		total.MinorUnits += ticket.Price.MinorUnits
+	}
+	return total
+}
+
+// PurchaseGroup books the same journey for every passenger of a group, all or
+// nothing, in a single payment taken from the first passenger. The passengers
+// are seated as close together as the section allows, and each gets a ticket
+// of their own carrying the group's booking reference, so passengers can be
+// cancelled one at a time with CancelBooking.
+func (s *TrainServer) PurchaseGroup(ctx context.Context, req *trainService.GroupBookingRequest) (*trainService.GroupBooking, error) {
+	tickets, err := s.validateGroup(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// As for a single ticket, the seats are held while the payment is taken,
+	// with the charge key on record.
+	holds, err := s.holdGroup(tickets)
+	if err != nil {
+		return nil, err
+	}
+	payment, chargeErr := s.charge(ctx, holds[0].ChargeKey, tickets[0].User.Email, totalPrice(tickets))
+	if chargeErr != nil {
+		err = paymentFailed(chargeErr)
+	}
+	return s.settleGroup(ctx, holds, payment, err)
+}
+
+// settleGroup books the tickets of the holds of a group once payment has been
+// taken for them, or handles err, the reason no payment could be taken. The
+// holds are released if the group is not booked, and the payment refunded.
+// Callers must not hold s.mu.
+func (s *TrainServer) settleGroup(ctx context.Context, holds []*trainService.SeatHold, payment string, err error) (*trainService.GroupBooking, error) {
+	s.mu.Lock()
+	var booking *trainService.GroupBooking
+	if err == nil {
+		booking, err = s.bookGroup(holds, payment)
+	}
+	for _, hold := range holds {
+		delete(s.paying, hold.Token)
+		if err == nil {
+			continue
+		}
+		if _, releaseErr := s.store.RemoveHold(hold.Token); releaseErr != nil && !errors.Is(releaseErr, errHoldNotFound) {
+			log.Printf("failed to release seat hold after a failed group purchase: %v", releaseErr)
+		}
+	}
+	if err != nil {
+		s.offerWaitlistSeats(holds[0].Ticket.DepartureId)
+	}
+	s.mu.Unlock()
+
+	if payment != "" && err != nil {
+		tickets := make([]*trainService.Ticket, 0, len(holds))
+		for _, hold := range holds {
+			tickets = append(tickets, hold.Ticket)
+		}
+		if refundErr := s.paymentProvider().Refund(context.WithoutCancel(ctx), payment, totalPrice(tickets)); refundErr != nil {
+			log.Printf("failed to refund payment %s for a group that was not booked: %v", payment, refundErr)
+		}
+	}
+	return booking, err
+}
+
+// validateGroup checks a group booking request and returns the ticket each
+// passenger is to get, with the promo code and currency normalised.
+func (s *TrainServer) validateGroup(req *trainService.GroupBookingRequest) ([]*trainService.Ticket, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if len(req.Passengers) == 0 {
+		return nil, invalidField("passengers", "a group booking needs at least one passenger")
+	}
+	if len(req.Passengers) > maxGroupSize {
+		return nil, invalidField("passengers", fmt.Sprintf("a group booking can have at most %d passengers", maxGroupSize))
+	}
+
+	tickets := make([]*trainService.Ticket, 0, len(req.Passengers))
+	for i, passenger := range req.Passengers {
+		if passenger == nil {
+			return nil, invalidField(fmt.Sprintf("passengers[%d]", i), "passenger is missing")
+		}
+		ticket := &trainService.Ticket{
+			DepartureId:    req.DepartureId,
+			From:           req.From,
+			To:             req.To,
+			Section:        req.Section,
+			User:           passenger.User,
+			PassengerType:  passenger.PassengerType,
+			AccessibleSeat: passenger.AccessibleSeat,
+			FareClass:      req.FareClass,
+			PromoCode:      req.PromoCode,
+			Currency:       req.Currency,
+		}
+		if err := s.validatePurchase(ticket); err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+// holdGroup holds a seat for each of the tickets PurchaseGroup is about to pay
+// for, or none if the whole group cannot be seated. The holds share the key
+// the group is to be charged under.
+func (s *TrainServer) holdGroup(tickets []*trainService.Ticket) ([]*trainService.SeatHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.prepareGroup(tickets); err != nil {
+		return nil, err
+	}
+	key, err := newToken()
+	if err != nil {
+		return nil, internalError("failed to generate charge key", err)
+	}
+	holds := make([]*trainService.SeatHold, 0, len(tickets))
+	for _, ticket := range tickets {
+		hold, err := s.newHold(ticket)
+		if err != nil {
+			return nil, err
+		}
+		hold.ChargeKey = key
+		hold.Group = true
+		holds = append(holds, hold)
+	}
+	if err := s.store.AddHolds(holds); err != nil {
+		return nil, internalError("failed to save seat holds", err)
+	}
+	s.publishAvailability(tickets[0].DepartureId)
+	for _, hold := range holds {
+		s.startPayment(hold.Token)
+	}
+	return holds, nil
+}
+
+// prepareGroup checks that the journey of a group can be booked for every
+// passenger, prices each ticket and seats the group together. The duplicate
+// policy does not apply, as passengers of a group often share a contact email.
+// Callers must hold s.mu.
+func (s *TrainServer) prepareGroup(tickets []*trainService.Ticket) error {
+	first := tickets[0]
+	departure, err := s.findDeparture(first.DepartureId)
+	if err != nil {
+		return err
+	}
+	if err := checkInOpen(departure); err != nil {
+		return err
+	}
+	if _, ok := sectionLayout(departure, first.Section); !ok {
+		return invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", first.Section, departure.Id))
+	}
+	travelled, err := validateLeg(departure, first.From, first.To)
+	if err != nil {
+		return err
+	}
+
+	for _, ticket := range tickets {
+		if err := checkConcession(ticket, departure, s.clock()); err != nil {
+			return err
+		}
+		ticket.DepartureId = departure.Id
+		fare, err := s.fare(departure, travelled, ticket, "")
+		if err != nil {
+			return err
+		}
+		ticket.Price = fare.Price
+		ticket.OriginalFare = fare.OriginalFare
+		ticket.Discounts = fare.Discounts
+	}
+	if err := s.checkGroupPromo(first.PromoCode, len(tickets)); err != nil {
+		return err
+	}
+	if free := s.legSeats(departure, first.Section, travelled, nil); free < len(tickets) {
+		return groupSoldOut(first.Section, max(free, 0), len(tickets))
+	}
+
+	accessible := make([]bool, len(tickets))
+	for i, ticket := range tickets {
+		accessible[i] = ticket.AccessibleSeat
+	}
+	seats, err := s.allocateGroupSeats(departure, first.Section, travelled, accessible)
+	if err != nil {
+		return err
+	}
+	for i, ticket := range tickets {
+		ticket.Seat = seats[i]
+	}
+	return nil
+}
+
+// checkGroupPromo checks that a promo code with a usage limit has a use left
+// for each of the passengers of a group. Callers must hold s.mu.
+func (s *TrainServer) checkGroupPromo(code string, passengers int) error {
+	if code == "" {
+		return nil
+	}
+	promo, err := s.store.FindPromoCode(code)
+	if err != nil || promo.MaxUses == 0 {
+		return nil
+	}
+	if left := promo.MaxUses - s.promoUses(code, ""); left < int32(passengers) {
+		return preconditionFailed("PROMO_USED_UP", "promo_code",
+			fmt.Sprintf("promo code %s has %d uses left, not enough for %d passengers", code, left, passengers))
+	}
+	return nil
+}
+
+// bookGroup books the tickets of holds as one group booking paid for by
+// payment. Callers must hold s.mu.
+func (s *TrainServer) bookGroup(holds []*trainService.SeatHold, payment string) (*trainService.GroupBooking, error) {
+	reference, err := s.newBookingReference()
+	if err != nil {
+		return nil, internalError("failed to generate booking reference", err)
+	}
+	booking := &trainService.GroupBooking{BookingReference: reference, PaymentReference: payment}
+	reserved := []string{reference}
+	tokens := make([]string, 0, len(holds))
+	for _, hold := range holds {
+		ticket := proto.Clone(hold.Ticket).(*trainService.Ticket)
+		ticketReference, err := s.newBookingReference(reserved...)
+		if err != nil {
+			return nil, internalError("failed to generate booking reference", err)
+		}
+		reserved = append(reserved, ticketReference)
+		ticket.BookingReference = ticketReference
+		ticket.GroupReference = reference
+		ticket.PaymentReference = payment
+		ticket.AmountPaid = proto.Clone(ticket.Price).(*trainService.Money)
+		booking.Tickets = append(booking.Tickets, ticket)
+		tokens = append(tokens, hold.Token)
+	}
+	if err := s.store.ConfirmHolds(tokens, booking.Tickets); err != nil {
+		return nil, internalError("failed to save tickets", err)
+	}
+	booking.Price = totalPrice(booking.Tickets)
+	return booking, nil
+}
+
+// GetGroupBooking returns the passengers of a group booking that are still
+// booked.
+func (s *TrainServer) GetGroupBooking(ctx context.Context, req *trainService.BookingReference) (*trainService.GroupBooking, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("reference field is empty", field{"reference", req.Reference}); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tickets := s.groupTickets(req.Reference)
+	if len(tickets) == 0 {
+		return nil, bookingNotFound(req.Reference)
+	}
+	return &trainService.GroupBooking{
+		BookingReference: req.Reference,
+		Tickets:          tickets,
+		Price:            totalPrice(tickets),
+		PaymentReference: tickets[0].PaymentReference,
+	}, nil
+}
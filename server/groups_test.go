@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testGroup returns a request booking section for passengers passengers on the
+// default departure, all sharing one contact email.
+func testGroup(section string, passengers int) *trainService.GroupBookingRequest {
+	req := &trainService.GroupBookingRequest{From: "London", To: "Paris", Section: section}
+	for i := 0; i < passengers; i++ {
+		req.Passengers = append(req.Passengers, &trainService.GroupPassenger{
+			User: &trainService.User{FirstName: fmt.Sprintf("Passenger%d", i+1), LastName: "Family", Email: "family@example.com"},
+		})
+	}
+	return req
+}
+
+func TestPurchaseGroup(t *testing.T) {
+	payments := newFakePaymentProvider(paymentApprove)
+	store := newTestStore(testLayout, nil)
+	server := &TrainServer{store: store, payments: payments, duplicates: duplicateReject}
+	ctx := context.Background()
+
+	// Row 1 of section A is left with three seats, too few for the group.
+	if _, err := server.PurchaseTicket(ctx, testTicket("solo@example.com", "A")); err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	booking, err := server.PurchaseGroup(ctx, testGroup("A", 4))
+	if err != nil {
+		t.Fatalf("PurchaseGroup failed: %v", err)
+	}
+	if len(booking.Tickets) != 4 {
+		t.Fatalf("Expected a ticket per passenger, got %v", booking.Tickets)
+	}
+	references := map[string]bool{}
+	var total int64
+	for i, ticket := range booking.Tickets {
+		if ticket.Seat.Row != 2 || ticket.Seat.Number != int32(i+1) {
+			t.Errorf("Expected the group seated together in row 2, got %s", seatLabel(ticket.Seat))
+		}
+		if ticket.GroupReference != booking.BookingReference || ticket.PaymentReference != booking.PaymentReference {
+			t.Errorf("Expected ticket %v to share the group booking", ticket)
+		}
+		references[ticket.BookingReference] = true
+		total += ticket.Price.MinorUnits
+	}
+	if len(references) != 4 || references[booking.BookingReference] {
+		t.Errorf("Expected each passenger to get a booking reference of their own, got %v", references)
+	}
+	if !proto.Equal(booking.Price, eur(total)) || !proto.Equal(payments.remaining(booking.PaymentReference), eur(total)) {
+		t.Errorf("Expected a single payment of %d, got %v", total, booking.Price)
+	}
+
+	// One passenger drops out and is refunded; the rest stay booked.
+	cancelled, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: booking.Tickets[1].BookingReference})
+	if err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if !proto.Equal(payments.remaining(booking.PaymentReference), eur(total-cancelled.Refund.Amount.MinorUnits)) {
+		t.Errorf("Expected the passenger refunded from the group payment, got %v", payments.remaining(booking.PaymentReference))
+	}
+	remaining, err := server.GetGroupBooking(ctx, &trainService.BookingReference{Reference: booking.BookingReference})
+	if err != nil {
+		t.Fatalf("GetGroupBooking failed: %v", err)
+	}
+	if len(remaining.Tickets) != 3 || !proto.Equal(remaining.Price, eur(total-booking.Tickets[1].Price.MinorUnits)) {
+		t.Errorf("Expected three passengers left, got %v", remaining)
+	}
+
+	// A group that does not fit in full is not booked at all.
+	free := store.SeatCount("", "A")
+	if _, err := server.PurchaseGroup(ctx, testGroup("A", free+1)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+	payments.outcome = paymentDecline
+	if _, err := server.PurchaseGroup(ctx, testGroup("A", 2)); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a declined payment, got %v", err)
+	}
+	if store.SeatCount("", "A") != free || len(store.Holds()) != 0 {
+		t.Errorf("Expected no seat taken by failed group purchases, got %d free and holds %v", store.SeatCount("", "A"), store.Holds())
+	}
+}
+
+func TestPurchaseGroupPromo(t *testing.T) {
+	server := &TrainServer{store: newTestStore(testLayout, nil)}
+	ctx := context.Background()
+
+	if _, err := server.CreatePromoCode(ctx, &trainService.PromoCode{Code: "FAMILY", PercentOff: 10, MaxUses: 3}); err != nil {
+		t.Fatalf("CreatePromoCode failed: %v", err)
+	}
+	req := testGroup("A", 4)
+	req.PromoCode = "family"
+	if _, err := server.PurchaseGroup(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition with too few promo uses left, got %v", err)
+	}
+	req = testGroup("A", 3)
+	req.PromoCode = "family"
+	booking, err := server.PurchaseGroup(ctx, req)
+	if err != nil {
+		t.Fatalf("PurchaseGroup failed: %v", err)
+	}
+	for _, ticket := range booking.Tickets {
+		if len(ticket.Discounts) != 1 {
+			t.Errorf("Expected the promo code applied to every passenger, got %v", ticket.Discounts)
+		}
+	}
+}
+
+func TestPurchaseGroupErrors(t *testing.T) {
+	server := &TrainServer{store: newTestStore(testLayout, nil)}
+	ctx := context.Background()
+
+	missingUser := testGroup("A", 2)
+	missingUser.Passengers[1].User = nil
+	missingPassenger := testGroup("A", 2)
+	missingPassenger.Passengers[0] = nil
+	tests := []struct {
+		name         string
+		request      *trainService.GroupBookingRequest
+		expectedCode codes.Code
+	}{
+		{name: "Nil request", request: nil, expectedCode: codes.InvalidArgument},
+		{name: "No passengers", request: testGroup("A", 0), expectedCode: codes.InvalidArgument},
+		{name: "Too many passengers", request: testGroup("A", maxGroupSize+1), expectedCode: codes.InvalidArgument},
+		{name: "Missing passenger", request: missingPassenger, expectedCode: codes.InvalidArgument},
+		{name: "Missing user", request: missingUser, expectedCode: codes.InvalidArgument},
+		{name: "Unknown section", request: testGroup("C", 2), expectedCode: codes.InvalidArgument},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := server.PurchaseGroup(ctx, tc.request); status.Code(err) != tc.expectedCode {
+				t.Errorf("Expected %v, got %v", tc.expectedCode, err)
+			}
+		})
+	}
+
+	for _, req := range []*trainService.BookingReference{nil, {}} {
+		if _, err := server.GetGroupBooking(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+	if _, err := server.GetGroupBooking(ctx, &trainService.BookingReference{Reference: "MISSING"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
@@ -0,0 +1,452 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultHoldTTL is how long a held seat is kept for checkout when the
+	// server has no other window configured.
+	defaultHoldTTL = 10 * time.Minute
+	// holdReapInterval is how often expired holds are released.
+	holdReapInterval = 15 * time.Second
+)
+
+// seatedTickets returns the tickets on departure that take a seat: the booked
+// ones and those of seat holds. Callers must hold s.mu.
+func (s *TrainServer) seatedTickets(departure string) []*trainService.Ticket {
+	tickets := departureTickets(s.store.Tickets(), departure)
+	for _, hold := range s.store.Holds() {
+		if hold.Ticket.DepartureId == departure {
+			tickets = append(tickets, hold.Ticket)
+		}
+	}
+	return tickets
+}
+
+func holdExpired(hold *trainService.SeatHold, now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, hold.ExpiresAt)
+	return err != nil || !now.Before(expires)
+}
+
+// newToken returns a random URL-safe token naming a seat hold or waitlist
+// entry.
+func newToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func holdNotFound(token string) error {
+	return withDetails(codes.NotFound, "seat hold not found or already released", &errdetails.ResourceInfo{
+		ResourceType: "seat hold",
+		ResourceName: token,
+	})
+}
+
+// HoldSeat prices a journey and takes a seat for it, as PurchaseTicket does,
+// without booking it. The seat is kept until ConfirmBooking pays for and books
+// it or the hold expires.
+func (s *TrainServer) HoldSeat(ctx context.Context, req *trainService.Ticket) (*trainService.SeatHold, error) {
+	if err := s.validatePurchase(req); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A ticket the hold would replace is only replaced on confirmation.
+	if _, err := s.prepareTicket(req); err != nil {
+		return nil, err
+	}
+	return s.placeHold(req)
+}
+
+// placeHold records a hold on the seat of req, which prepareTicket has priced
+// and seated. Callers must hold s.mu.
+func (s *TrainServer) placeHold(req *trainService.Ticket) (*trainService.SeatHold, error) {
+	hold, err := s.newHold(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.AddHold(hold); err != nil {
+		return nil, internalError("failed to save seat hold", err)
+	}
+	s.publishAvailability(req.DepartureId)
+	return hold, nil
+}
+
+// newHold returns a hold on the seat of req running for the hold TTL from now.
+func (s *TrainServer) newHold(req *trainService.Ticket) (*trainService.SeatHold, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, internalError("failed to generate hold token", err)
+	}
+	ttl := s.holdTTL
+	if ttl <= 0 {
+		ttl = defaultHoldTTL
+	}
+	return &trainService.SeatHold{
+		Token:     token,
+		Ticket:    req,
+		ExpiresAt: s.clock().Add(ttl).UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// holdForPayment holds a seat for the ticket PurchaseTicket is about to pay
+// for.
+func (s *TrainServer) holdForPayment(req *trainService.Ticket) (*trainService.SeatHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.prepareTicket(req); err != nil {
+		return nil, err
+	}
+	hold, err := s.placeHold(req)
+	if err != nil {
+		return nil, err
+	}
+	s.startPayment(hold.Token)
+	return hold, nil
+}
+
+// ConfirmBooking takes payment for the ticket of a seat hold and books it at
+// the held seat and price. A declined payment keeps the hold so it can be
+// confirmed again until it expires.
+func (s *TrainServer) ConfirmBooking(ctx context.Context, req *trainService.ConfirmBookingRequest) (*trainService.Ticket, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("hold_token field is empty", field{"hold_token", req.HoldToken}); err != nil {
+		return nil, err
+	}
+
+	hold, err := s.claimHold(req.HoldToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.payForHold(ctx, hold, false)
+}
+
+// claimHold checks that the hold with the given token can be confirmed and
+// marks its payment as started.
+func (s *TrainServer) claimHold(token string) (*trainService.SeatHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold, err := s.store.FindHold(token)
+	if errors.Is(err, errHoldNotFound) {
+		return nil, holdNotFound(token)
+	}
+	if err != nil {
+		return nil, internalError("failed to find seat hold", err)
+	}
+	if s.paying[hold.Token] {
+		return nil, status.Error(codes.Aborted, "payment for this seat hold is already being taken")
+	}
+	// The reaper may not have got to an expired hold yet. One that was
+	// charged before is left to settleInterruptedCharges, as the charge may
+	// have gone through.
+	if holdExpired(hold, s.clock()) {
+		if hold.ChargeKey == "" {
+			if _, err := s.store.RemoveHold(hold.Token); err != nil {
+				return nil, internalError("failed to release seat hold", err)
+			}
+			s.offerWaitlistSeats(hold.Ticket.DepartureId)
+		}
+		return nil, preconditionFailed("HOLD_EXPIRED", "hold_token",
+			fmt.Sprintf("seat hold expired at %s, hold the seat again", hold.ExpiresAt))
+	}
+	// The user may have booked on the departure since the seat was held; that
+	// is checked again before booking, but is best caught before charging.
+	if _, err := s.duplicateOf(hold.Ticket); err != nil {
+		return nil, err
+	}
+	s.startPayment(hold.Token)
+	return hold, nil
+}
+
+// startPayment keeps the reaper and other confirmations away from a hold
+// while its payment is taken. Callers must hold s.mu.
+func (s *TrainServer) startPayment(token string) {
+	if s.paying == nil {
+		s.paying = map[string]bool{}
+	}
+	s.paying[token] = true
+}
+
+// payForHold charges for the ticket of hold and books it. The hold is released
+// if the charge fails and release is set, and kept otherwise. A charge that
+// cannot be booked is refunded, as is a ticket the booking replaces. Callers
+// must not hold s.mu.
+func (s *TrainServer) payForHold(ctx context.Context, hold *trainService.SeatHold, release bool) (*trainService.Ticket, error) {
+	// The charge key is on record before the charge is taken, so a charge the
+	// server stops during is settled when it starts again.
+	var payment string
+	recorded, err := s.recordChargeKey(hold)
+	if err == nil {
+		hold = recorded
+		var chargeErr error
+		if payment, chargeErr = s.charge(ctx, hold.ChargeKey, hold.Ticket.User.Email, hold.Ticket.Price); chargeErr != nil {
+			err = paymentFailed(chargeErr)
+		}
+	}
+	return s.settleCharge(ctx, hold, payment, err, release)
+}
+
+// recordChargeKey gives hold a new charge key and saves it. A hold charged
+// before keeps its key, so charging it again cannot take a second payment if
+// the first went through unnoticed.
+func (s *TrainServer) recordChargeKey(hold *trainService.SeatHold) (*trainService.SeatHold, error) {
+	if hold.ChargeKey != "" {
+		return hold, nil
+	}
+	key, err := newToken()
+	if err != nil {
+		return nil, internalError("failed to generate charge key", err)
+	}
+	recorded := proto.Clone(hold).(*trainService.SeatHold)
+	recorded.ChargeKey = key
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.UpdateHold(recorded); err != nil {
+		return nil, internalError("failed to record charge", err)
+	}
+	return recorded, nil
+}
+
+// settleCharge books the ticket of hold once payment has been taken for it, or
+// handles err, the reason no payment could be taken, as payForHold describes.
+// Callers must not hold s.mu.
+func (s *TrainServer) settleCharge(ctx context.Context, hold *trainService.SeatHold, payment string, err error, release bool) (*trainService.Ticket, error) {
+	s.mu.Lock()
+	delete(s.paying, hold.Token)
+	var ticket, replaced *trainService.Ticket
+	if err == nil {
+		ticket, replaced, err = s.bookHold(hold, payment)
+	}
+	if err != nil && release {
+		if _, releaseErr := s.store.RemoveHold(hold.Token); releaseErr != nil && !errors.Is(releaseErr, errHoldNotFound) {
+			log.Printf("failed to release seat hold after a failed purchase: %v", releaseErr)
+		}
+	}
+	// A confirmed offer leaves the waitlist and a released seat goes to it.
+	s.offerWaitlistSeats(hold.Ticket.DepartureId)
+	s.mu.Unlock()
+
+	if payment != "" && err != nil {
+		if refundErr := s.paymentProvider().Refund(context.WithoutCancel(ctx), payment, hold.Ticket.Price); refundErr != nil {
+			log.Printf("failed to refund payment %s for a ticket that was not booked: %v", payment, refundErr)
+		}
+	}
+	if replaced != nil {
+		s.refund(ctx, replaced)
+	}
+	return ticket, err
+}
+
+// bookHold books the ticket of hold, paid for by payment. It returns the
+// ticket and the cancelled ticket it replaced, if any. Callers must hold s.mu.
+func (s *TrainServer) bookHold(hold *trainService.SeatHold, payment string) (*trainService.Ticket, *trainService.Ticket, error) {
+	ticket := proto.Clone(hold.Ticket).(*trainService.Ticket)
+	replaced, err := s.duplicateOf(ticket)
+	if err != nil {
+		return nil, nil, err
+	}
+	replacedReference := ""
+	if replaced != nil {
+		replacedReference = replaced.BookingReference
+	}
+	reference, err := s.newBookingReference()
+	if err != nil {
+		return nil, nil, internalError("failed to generate booking reference", err)
+	}
+	ticket.BookingReference = reference
+	ticket.PaymentReference = payment
+	ticket.AmountPaid = proto.Clone(ticket.Price).(*trainService.Money)
+	if err := s.store.ConfirmHold(hold.Token, replacedReference, ticket); err != nil {
+		return nil, nil, internalError("failed to save ticket", err)
+	}
+	if replaced != nil {
+		replaced = s.cancelled(replaced)
+	}
+	return ticket, replaced, nil
+}
+
+// settleInterruptedCharges settles the charges left on record with no payment
+// being taken for them any more, because the server stopped during the charge
+// or the charge failed. A charge that went through makes the booking or
+// upgrade it paid for, as the call taking it would have, or is refunded if it
+// can no longer be made. Without a charge, a seat hold is kept until it is
+// confirmed again or expires, and the seats of a group or an upgrade are
+// released. A charge that cannot be looked up is looked up again the next
+// time, and its seats are not released meanwhile. Callers must not hold s.mu.
+func (s *TrainServer) settleInterruptedCharges(ctx context.Context) {
+	s.mu.Lock()
+	var interrupted []*trainService.SeatHold
+	var groupKeys []string
+	groups := map[string][]*trainService.SeatHold{}
+	for _, hold := range s.store.Holds() {
+		if hold.ChargeKey == "" || s.paying[hold.Token] {
+			continue
+		}
+		s.startPayment(hold.Token)
+		if !hold.Group {
+			interrupted = append(interrupted, hold)
+			continue
+		}
+		if groups[hold.ChargeKey] == nil {
+			groupKeys = append(groupKeys, hold.ChargeKey)
+		}
+		groups[hold.ChargeKey] = append(groups[hold.ChargeKey], hold)
+	}
+	var upgrades []*trainService.PendingUpgrade
+	for _, upgrade := range s.store.PendingUpgrades() {
+		if s.paying[upgrade.ChargeKey] {
+			continue
+		}
+		s.startPayment(upgrade.ChargeKey)
+		if upgrade.HoldToken != "" {
+			s.startPayment(upgrade.HoldToken)
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+	s.mu.Unlock()
+
+	for _, hold := range interrupted {
+		payment, found := s.findCharge(ctx, hold.ChargeKey)
+		switch {
+		case !found:
+			s.stopPayment(hold.Token)
+		case payment == "":
+			s.settleCharge(ctx, hold, "", paymentFailed(errPaymentNotFound), holdExpired(hold, s.clock()))
+		default:
+			if ticket, err := s.settleCharge(ctx, hold, payment, nil, true); err != nil {
+				log.Printf("failed to book seat hold %s after an interrupted charge, payment %s was refunded: %v", hold.Token, payment, err)
+			} else {
+				log.Printf("Booked %s for an interrupted charge", ticket.BookingReference)
+			}
+		}
+	}
+	for _, key := range groupKeys {
+		holds := groups[key]
+		payment, found := s.findCharge(ctx, key)
+		switch {
+		case !found:
+			for _, hold := range holds {
+				s.stopPayment(hold.Token)
+			}
+		case payment == "":
+			s.settleGroup(ctx, holds, "", paymentFailed(errPaymentNotFound))
+		default:
+			if booking, err := s.settleGroup(ctx, holds, payment, nil); err != nil {
+				log.Printf("failed to book a group after an interrupted charge, payment %s was refunded: %v", payment, err)
+			} else {
+				log.Printf("Booked group %s for an interrupted charge", booking.BookingReference)
+			}
+		}
+	}
+	for _, upgrade := range upgrades {
+		payment, found := s.findCharge(ctx, upgrade.ChargeKey)
+		switch {
+		case !found:
+			s.stopPayment(upgrade.ChargeKey, upgrade.HoldToken)
+		case payment == "":
+			s.settleUpgrade(ctx, upgrade, "", paymentFailed(errPaymentNotFound))
+		default:
+			if _, err := s.settleUpgrade(ctx, upgrade, payment, nil); err != nil {
+				log.Printf("failed to upgrade %s after an interrupted charge, payment %s was refunded: %v", upgrade.Original.BookingReference, payment, err)
+			} else {
+				log.Printf("Upgraded %s for an interrupted charge", upgrade.Original.BookingReference)
+			}
+		}
+	}
+}
+
+// findCharge returns the payment taken by the charge with the given key, or
+// an empty reference if it did not go through. found is false if the payment
+// provider could not tell.
+func (s *TrainServer) findCharge(ctx context.Context, key string) (payment string, found bool) {
+	payment, err := s.paymentProvider().FindCharge(ctx, key)
+	if errors.Is(err, errPaymentNotFound) {
+		return "", true
+	}
+	if err != nil {
+		log.Printf("failed to look up charge %s: %v", key, err)
+		return "", false
+	}
+	return payment, true
+}
+
+// stopPayment undoes startPayment for each of tokens, letting the reaper and
+// other confirmations back at them. Callers must not hold s.mu.
+func (s *TrainServer) stopPayment(tokens ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, token := range tokens {
+		delete(s.paying, token)
+	}
+}
+
+// releaseExpiredHolds gives the seats of expired holds back, offering them to
+// the waitlist, and returns how many holds were released. Holds whose payment
+// is being taken are left to the payment to settle, and holds that were
+// charged to settleInterruptedCharges.
+func (s *TrainServer) releaseExpiredHolds() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	charged := map[string]bool{}
+	for _, upgrade := range s.store.PendingUpgrades() {
+		charged[upgrade.HoldToken] = true
+	}
+	released := 0
+	now := s.clock()
+	for _, hold := range s.store.Holds() {
+		if !holdExpired(hold, now) || s.paying[hold.Token] || hold.ChargeKey != "" || charged[hold.Token] {
+			continue
+		}
+		if _, err := s.store.RemoveHold(hold.Token); err != nil {
+			return released, err
+		}
+		released++
+		s.offerWaitlistSeats(hold.Ticket.DepartureId)
+	}
+	return released, nil
+}
+
+// reapHolds settles interrupted charges and releases expired holds every
+// interval until ctx is done, so seats of abandoned checkouts go back on
+// sale.
+func (s *TrainServer) reapHolds(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.settleInterruptedCharges(ctx)
+			if released, err := s.releaseExpiredHolds(); err != nil {
+				log.Printf("failed to release expired seat holds: %v", err)
+			} else if released > 0 {
+				log.Printf("Released %d expired seat holds", released)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSeatHolds(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := newRouteStore()
+	server := &TrainServer{store: store, duplicates: duplicateReject, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	// Section A has two seats.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	journey := func(email string) *trainService.Ticket {
+		return testJourney(email, departure.Id, "A")
+	}
+
+	first, err := server.HoldSeat(ctx, journey("first@example.com"))
+	if err != nil {
+		t.Fatalf("HoldSeat failed: %v", err)
+	}
+	second, err := server.HoldSeat(ctx, journey("second@example.com"))
+	if err != nil {
+		t.Fatalf("HoldSeat failed: %v", err)
+	}
+	if keyOf(first.Ticket.Seat) == keyOf(second.Ticket.Seat) {
+		t.Errorf("Expected holds on different seats, got %v and %v", first.Ticket.Seat, second.Ticket.Seat)
+	}
+	if first.Ticket.BookingReference != "" || !proto.Equal(first.Ticket.Price, eur(7925)) {
+		t.Errorf("Expected a priced hold without a booking reference, got %v", first.Ticket)
+	}
+	if _, err := server.PurchaseTicket(ctx, journey("third@example.com")); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected held seats not to be sold, got %v", err)
+	}
+
+	ticket, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: first.Token})
+	if err != nil {
+		t.Fatalf("ConfirmBooking failed: %v", err)
+	}
+	if ticket.BookingReference == "" || !proto.Equal(ticket.Seat, first.Ticket.Seat) || !proto.Equal(ticket.Price, first.Ticket.Price) {
+		t.Errorf("Expected the held seat and price to be booked, got %v", ticket)
+	}
+	if store.SeatCount(departure.Id, "A") != 0 {
+		t.Errorf("Expected confirming to keep the seat taken, got %d free", store.SeatCount(departure.Id, "A"))
+	}
+	if _, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: first.Token}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a confirmed hold, got %v", err)
+	}
+	if _, err := server.HoldSeat(ctx, journey("first@example.com")); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists holding a second seat for a booked user, got %v", err)
+	}
+
+	// An expired hold cannot be confirmed and its seat goes back on sale.
+	now = now.Add(defaultHoldTTL)
+	if _, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: second.Token}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for an expired hold, got %v", err)
+	}
+	if store.SeatCount(departure.Id, "A") != 1 || len(store.Holds()) != 0 {
+		t.Errorf("Expected the expired hold released, got %d free seats and holds %v", store.SeatCount(departure.Id, "A"), store.Holds())
+	}
+
+	for _, req := range []*trainService.ConfirmBookingRequest{nil, {}} {
+		if _, err := server.ConfirmBooking(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+}
+
+func TestReapHolds(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := newRouteStore()
+	server := &TrainServer{store: store, holdTTL: time.Minute, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		ticket := testJourney(email, departure.Id, "B")
+		ticket.To = "LIL"
+		if _, err := server.HoldSeat(ctx, ticket); err != nil {
+			t.Fatalf("HoldSeat failed: %v", err)
+		}
+		now = now.Add(30 * time.Second)
+	}
+
+	// Only the first hold has run out a minute after it was placed.
+	if released, err := server.releaseExpiredHolds(); err != nil || released != 1 {
+		t.Errorf("Expected 1 hold released, got %d (%v)", released, err)
+	}
+	if counts := store.SegmentSeatCounts(departure.Id, "B"); counts[0] != 3 || counts[1] != 4 {
+		t.Errorf("Expected one seat held on LON-LIL, got %v", counts)
+	}
+
+	now = now.Add(time.Minute)
+	reaperCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		server.reapHolds(reaperCtx, time.Millisecond)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		server.mu.RLock()
+		holds := len(store.Holds())
+		server.mu.RUnlock()
+		if holds == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the reaper to release the remaining hold")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	<-done
+}
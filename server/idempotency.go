@@ -0,0 +1,252 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// idempotencyKeyHeader is the gRPC metadata key clients send a retry-safe
+	// key for a mutating call in.
+	idempotencyKeyHeader = "idempotency-key"
+	// defaultIdempotencyTTL is how long the result of a keyed call is kept
+	// when the server has no other window configured.
+	defaultIdempotencyTTL = 24 * time.Hour
+	// defaultIdempotencyMaxKeys is how many keyed calls are kept when the
+	// server has no other bound configured.
+	defaultIdempotencyMaxKeys = 100000
+	maxIdempotencyKeyLen      = 255
+)
+
+// mutatingMethods are the RPCs that change bookings, stations, routes or
+// departures and so accept an idempotency key.
+var mutatingMethods = map[string]bool{
+	trainService.TrainService_PurchaseTicket_FullMethodName:  true,
+	trainService.TrainService_CancelTicket_FullMethodName:    true,
+	trainService.TrainService_ModifyUserSeat_FullMethodName:  true,
+	trainService.TrainService_SwapSeats_FullMethodName:       true,
+	trainService.TrainService_CancelBooking_FullMethodName:   true,
+	trainService.TrainService_CreateDeparture_FullMethodName: true,
+	trainService.TrainService_AddStation_FullMethodName:      true,
+	trainService.TrainService_CreateRoute_FullMethodName:     true,
+	trainService.TrainService_CreatePromoCode_FullMethodName: true,
+	trainService.TrainService_HoldSeat_FullMethodName:        true,
+	trainService.TrainService_ConfirmBooking_FullMethodName:  true,
+	trainService.TrainService_JoinWaitlist_FullMethodName:    true,
+	trainService.TrainService_LeaveWaitlist_FullMethodName:   true,
+	trainService.TrainService_ResizeSection_FullMethodName:   true,
+	trainService.TrainService_PurchaseGroup_FullMethodName:   true,
+	trainService.TrainService_UpgradeTicket_FullMethodName:   true,
+	trainService.TrainService_CheckIn_FullMethodName:         true,
+	trainService.TrainService_CloseCheckIn_FullMethodName:    true,
+}
+
+// idempotencyCache remembers the outcome of mutating calls by idempotency key,
+// so a client retrying a call it did not hear back from gets the original
+// outcome instead of making the change twice. Keys are scoped to the host
+// calling, so one client's key never answers another's call, and at most
+// maxKeys calls are kept, forgetting the oldest first.
+type idempotencyCache struct {
+	ttl     time.Duration
+	maxKeys int
+	now     func() time.Time
+
+	mu    sync.Mutex
+	calls map[string]*idempotentCall // caller and key -> call
+	// order has the calls in the order they started, which is also the order
+	// they expire in.
+	order *list.List
+}
+
+// idempotentCall is a call made with an idempotency key.
+type idempotentCall struct {
+	key         string
+	element     *list.Element
+	fingerprint [sha256.Size]byte
+	// done is closed once resp and err are set.
+	done    chan struct{}
+	resp    proto.Message
+	err     error
+	expires time.Time
+}
+
+// newIdempotencyCache returns a cache keeping up to maxKeys outcomes for ttl,
+// or the defaults for either if it is not positive.
+func newIdempotencyCache(ttl time.Duration, maxKeys int, now func() time.Time) *idempotencyCache {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	if maxKeys <= 0 {
+		maxKeys = defaultIdempotencyMaxKeys
+	}
+	return &idempotencyCache{ttl: ttl, maxKeys: maxKeys, now: now, calls: map[string]*idempotentCall{}, order: list.New()}
+}
+
+// intercept runs a mutating call carrying an idempotency key at most once per
+// key within the retention window. A repeated key gets the outcome of the
+// first call, waiting for it if it is still running, and a key reused for a
+// different method or request fails with IDEMPOTENCY_KEY_REUSED. Outcomes a
+// retry could change, such as an unavailable payment provider, are not kept,
+// so the retry runs the call again.
+func (c *idempotencyCache) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !mutatingMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	key, err := idempotencyKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return handler(ctx, req)
+	}
+	// Handlers normalise their requests, so the fingerprint is taken first.
+	fingerprint, err := requestFingerprint(info.FullMethod, req)
+	if err != nil {
+		return nil, internalError("failed to fingerprint request", err)
+	}
+
+	call, first, err := c.start(idempotencyCaller(ctx), key, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		if call.resp == nil {
+			return nil, call.err
+		}
+		return proto.Clone(call.resp), call.err
+	}
+
+	resp, err := handler(ctx, req)
+	c.finish(call, resp, err)
+	return resp, err
+}
+
+// idempotencyCaller returns the host a call came from. The port is left out,
+// as a client retrying over a new connection gets a new one.
+func idempotencyCaller(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// idempotencyKey returns the idempotency key sent with a call, if any.
+func idempotencyKey(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(idempotencyKeyHeader)
+	switch {
+	case len(values) == 0:
+		return "", nil
+	case len(values) > 1:
+		return "", invalidField(idempotencyKeyHeader, "only one idempotency key can be sent")
+	case values[0] == "" || len(values[0]) > maxIdempotencyKeyLen:
+		return "", invalidField(idempotencyKeyHeader, fmt.Sprintf("idempotency key must be 1 to %d characters", maxIdempotencyKeyLen))
+	}
+	return values[0], nil
+}
+
+func requestFingerprint(method string, req any) ([sha256.Size]byte, error) {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return [sha256.Size]byte{}, fmt.Errorf("request of %s is not a protobuf message", method)
+	}
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(append([]byte(method+"\x00"), payload...)), nil
+}
+
+// start returns the call recorded for key sent by caller, and whether it is a
+// new one the caller must run and finish.
+func (c *idempotencyCache) start(caller, key string, fingerprint [sha256.Size]byte) (*idempotentCall, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.expire(now)
+	scoped := caller + "\x00" + key
+	if call, ok := c.calls[scoped]; ok {
+		if call.fingerprint != fingerprint {
+			return nil, false, preconditionFailed("IDEMPOTENCY_KEY_REUSED", idempotencyKeyHeader+":"+key,
+				"idempotency key was already used for a different request")
+		}
+		return call, false, nil
+	}
+	for len(c.calls) >= c.maxKeys {
+		c.forget(c.order.Front().Value.(*idempotentCall))
+	}
+	call := &idempotentCall{key: scoped, fingerprint: fingerprint, done: make(chan struct{}), expires: now.Add(c.ttl)}
+	call.element = c.order.PushBack(call)
+	c.calls[scoped] = call
+	return call, true, nil
+}
+
+// finish records the outcome of call, forgetting the key if a retry should run
+// the call again.
+func (c *idempotencyCache) finish(call *idempotentCall, resp any, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if message, ok := resp.(proto.Message); ok && err == nil {
+		call.resp = proto.Clone(message)
+	}
+	call.err = err
+	// The call may have been forgotten already to make room for newer ones.
+	if retryable(err) && c.calls[call.key] == call {
+		c.forget(call)
+	}
+	close(call.done)
+}
+
+// retryable reports whether a call that failed with err may succeed if made
+// again unchanged.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.DeadlineExceeded, codes.Canceled, codes.Internal:
+		return true
+	}
+	return false
+}
+
+// expire forgets the calls whose retention window has passed. Callers must
+// hold c.mu.
+func (c *idempotencyCache) expire(now time.Time) {
+	for c.order.Len() > 0 {
+		call := c.order.Front().Value.(*idempotentCall)
+		if now.Before(call.expires) {
+			return
+		}
+		c.forget(call)
+	}
+}
+
+// forget drops call, so its key runs the next call made with it. Callers must
+// hold c.mu.
+func (c *idempotencyCache) forget(call *idempotentCall) {
+	delete(c.calls, call.key)
+	c.order.Remove(call.element)
+}
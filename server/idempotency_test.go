@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func withIdempotencyKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, key))
+}
+
+func TestIdempotentPurchase(t *testing.T) {
+	store := newTestStore(testLayout, nil)
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: store, payments: payments, duplicates: duplicateAllow}
+	cache := newIdempotencyCache(time.Hour, 0, time.Now)
+	info := &grpc.UnaryServerInfo{FullMethod: trainService.TrainService_PurchaseTicket_FullMethodName}
+	purchase := func(ctx context.Context, req any) (any, error) {
+		return server.PurchaseTicket(ctx, req.(*trainService.Ticket))
+	}
+
+	// A retry with the same key gets the original ticket and is not charged
+	// again.
+	first, err := cache.intercept(withIdempotencyKey("retry-1"), testTicket("test@example.com", "A"), info, purchase)
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	retried, err := cache.intercept(withIdempotencyKey("retry-1"), testTicket("test@example.com", "A"), info, purchase)
+	if err != nil {
+		t.Fatalf("Retried PurchaseTicket failed: %v", err)
+	}
+	if !proto.Equal(first.(proto.Message), retried.(proto.Message)) || len(store.Tickets()) != 1 || len(payments.payments) != 1 {
+		t.Errorf("Expected one ticket and payment returned twice, got %v and %v with %d tickets", first, retried, len(store.Tickets()))
+	}
+
+	// The same key cannot be used for another request.
+	if _, err := cache.intercept(withIdempotencyKey("retry-1"), testTicket("other@example.com", "A"), info, purchase); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a reused key, got %v", err)
+	}
+	cancel := &grpc.UnaryServerInfo{FullMethod: trainService.TrainService_CancelTicket_FullMethodName}
+	if _, err := cache.intercept(withIdempotencyKey("retry-1"), testTicket("test@example.com", "A"), cancel, purchase); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a key reused on another method, got %v", err)
+	}
+
+	// Calls without a key are not deduplicated.
+	for i := 0; i < 2; i++ {
+		if _, err := cache.intercept(context.Background(), testTicket("test@example.com", "A"), info, purchase); err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+	}
+	if len(store.Tickets()) != 3 {
+		t.Errorf("Expected two more tickets, got %d", len(store.Tickets()))
+	}
+}
+
+func TestIdempotencyCache(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := newIdempotencyCache(time.Hour, 0, func() time.Time { return now })
+	info := &grpc.UnaryServerInfo{FullMethod: trainService.TrainService_CancelBooking_FullMethodName}
+	req := &trainService.BookingReference{Reference: "ABC123"}
+
+	calls := 0
+	outcomes := []error{status.Error(codes.Unavailable, "try again"), status.Error(codes.NotFound, "no booking"), nil}
+	handler := func(ctx context.Context, req any) (any, error) {
+		err := outcomes[calls]
+		calls++
+		if err != nil {
+			return nil, err
+		}
+		return &trainService.Ticket{BookingReference: "ABC123"}, nil
+	}
+	call := func(key string) error {
+		_, err := cache.intercept(withIdempotencyKey(key), req, info, handler)
+		return err
+	}
+
+	// An outcome a retry could change is not kept, a final one is.
+	if err := call("key"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Expected Unavailable, got %v", err)
+	}
+	if err := call("key"); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected the retry to run, got %v", err)
+	}
+	if err := call("key"); status.Code(err) != codes.NotFound || calls != 2 {
+		t.Errorf("Expected the kept NotFound without running the call, got %v after %d calls", err, calls)
+	}
+
+	// Keys are forgotten after the retention window.
+	now = now.Add(time.Hour)
+	if err := call("key"); err != nil || calls != 3 {
+		t.Errorf("Expected the call to run again once the key expired, got %v after %d calls", err, calls)
+	}
+
+	// Read-only calls and invalid keys.
+	read := &grpc.UnaryServerInfo{FullMethod: trainService.TrainService_GetBooking_FullMethodName}
+	if _, err := cache.intercept(withIdempotencyKey("key"), &trainService.User{}, read, func(context.Context, any) (any, error) { return nil, nil }); err != nil {
+		t.Errorf("Expected read-only calls to ignore the key, got %v", err)
+	}
+	twice := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyHeader, "a", idempotencyKeyHeader, "b"))
+	if _, err := cache.intercept(twice, req, info, handler); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for two keys, got %v", err)
+	}
+}
+
+func TestIdempotencyCacheScope(t *testing.T) {
+	cache := newIdempotencyCache(time.Hour, 2, time.Now)
+	info := &grpc.UnaryServerInfo{FullMethod: trainService.TrainService_CheckIn_FullMethodName}
+	req := &trainService.BookingReference{Reference: "ABC123"}
+
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return &trainService.Ticket{BookingReference: "ABC123", CheckedIn: true}, nil
+	}
+	call := func(host string, port int, key string) {
+		ctx := peer.NewContext(withIdempotencyKey(key), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(host), Port: port}})
+		if _, err := cache.intercept(ctx, req, info, handler); err != nil {
+			t.Fatalf("CheckIn failed: %v", err)
+		}
+	}
+
+	// A client retrying over a new connection gets its outcome, another
+	// client sending the same key does not.
+	call("192.0.2.1", 5000, "key")
+	call("192.0.2.1", 5001, "key")
+	if calls != 1 {
+		t.Errorf("Expected the retry from another port to be answered, got %d calls", calls)
+	}
+	call("192.0.2.2", 5000, "key")
+	if calls != 2 {
+		t.Errorf("Expected another client's key to run the call, got %d calls", calls)
+	}
+
+	// Only the latest two keys are kept.
+	call("192.0.2.1", 5000, "other")
+	if len(cache.calls) != 2 || cache.order.Len() != 2 {
+		t.Errorf("Expected two kept keys, got %d", len(cache.calls))
+	}
+	call("192.0.2.1", 5000, "key")
+	if calls != 4 {
+		t.Errorf("Expected the oldest key to be forgotten, got %d calls", calls)
+	}
+}
+
+func TestIdempotencyConcurrentRetry(t *testing.T) {
+	cache := newIdempotencyCache(time.Hour, 0, time.Now)
+	info := &grpc.UnaryServerInfo{FullMethod: trainService.TrainService_CheckIn_FullMethodName}
+	req := &trainService.BookingReference{Reference: "ABC123"}
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return &trainService.Ticket{BookingReference: "ABC123", CheckedIn: true}, nil
+	}
+
+	// A retry arriving while the first call runs waits for its outcome.
+	var wg sync.WaitGroup
+	responses := make([]any, 2)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], _ = cache.intercept(withIdempotencyKey("key"), req, info, handler)
+		}(i)
+	}
+	for {
+		cache.mu.Lock()
+		started := len(cache.calls) == 1
+		cache.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if calls != 1 || !proto.Equal(responses[0].(proto.Message), responses[1].(proto.Message)) {
+		t.Errorf("Expected one call answering both, got %d calls and %v", calls, responses)
+	}
+}
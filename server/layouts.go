@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/status"
+)
+
+// defaultSeatClass is the class of sections that do not name one.
+const defaultSeatClass = "standard"
+
+// layoutTable is the coach configuration: the train layouts departures can be
+// created with, by name, and the one the default departure uses.
+type layoutTable struct {
+	Default string                     `json:"default"`
+	Layouts map[string][]sectionConfig `json:"layouts"`
+}
+
+type sectionConfig struct {
+	Section         string       `json:"section"`
+	Rows            int32        `json:"rows"`
+	SeatsPerRow     int32        `json:"seats_per_row"`
+	Class           string       `json:"class"`
+	FareFactor      float32      `json:"fare_factor"`
+	AccessibleSeats []seatConfig `json:"accessible_seats"`
+	// OverbookingPercent of the seats may be sold on top of them.
+	OverbookingPercent int32 `json:"overbooking_percent"`
+}
+
+type seatConfig struct {
+	Row    int32 `json:"row"`
+	Number int32 `json:"number"`
+}
+
+// defaultLayouts is the original two section train.
+var defaultLayouts = layoutTable{
+	Default: "standard",
+	Layouts: map[string][]sectionConfig{
+		"standard": {
+			{Section: "A", Rows: 5, SeatsPerRow: 4},
+			{Section: "B", Rows: 5, SeatsPerRow: 4},
+		},
+	},
+}
+
+// loadLayoutTable reads a coach configuration from a JSON file.
+func loadLayoutTable(path string) (*layoutTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t layoutTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("invalid coach layouts in %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+func (t *layoutTable) validate() error {
+	if _, ok := t.Layouts[t.Default]; !ok {
+		return fmt.Errorf("default layout %q is not defined", t.Default)
+	}
+	for name := range t.Layouts {
+		sections, _ := t.sections(name)
+		if err := validateSections(sections); err != nil {
+			return fmt.Errorf("layout %s: %s", name, status.Convert(err).Message())
+		}
+	}
+	return nil
+}
+
+// sections returns new copies of the sections of the layout called name.
+func (t *layoutTable) sections(name string) ([]*trainService.SectionCapacity, bool) {
+	configs, ok := t.Layouts[name]
+	if !ok {
+		return nil, false
+	}
+	sections := make([]*trainService.SectionCapacity, 0, len(configs))
+	for _, config := range configs {
+		section := &trainService.SectionCapacity{
+			Section:            config.Section,
+			Rows:               config.Rows,
+			SeatsPerRow:        config.SeatsPerRow,
+			SeatClass:          normaliseSeatClass(config.Class),
+			FareFactor:         config.FareFactor,
+			OverbookingPercent: config.OverbookingPercent,
+		}
+		for _, seat := range config.AccessibleSeats {
+			section.AccessibleSeats = append(section.AccessibleSeats, &trainService.Seat{Row: seat.Row, Number: seat.Number})
+		}
+		sections = append(sections, section)
+	}
+	return sections, true
+}
+
+func normaliseSeatClass(class string) string {
+	class = strings.ToLower(strings.TrimSpace(class))
+	if class == "" {
+		return defaultSeatClass
+	}
+	return class
+}
+
+func (s *TrainServer) layoutTable() *layoutTable {
+	if s.layouts != nil {
+		return s.layouts
+	}
+	return &defaultLayouts
+}
+
+// ListLayouts streams the configured train layouts by name.
+func (s *TrainServer) ListLayouts(req *trainService.ListLayoutsRequest, stream trainService.TrainService_ListLayoutsServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+
+	table := s.layoutTable()
+	names := make([]string, 0, len(table.Layouts))
+	for name := range table.Layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sections, _ := table.sections(name)
+		if err := stream.Send(&trainService.TrainLayout{Name: name, Sections: sections}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
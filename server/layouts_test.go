@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type layoutStream struct {
+	grpc.ServerStream
+	data []*trainService.TrainLayout
+}
+
+func (s *layoutStream) Send(layout *trainService.TrainLayout) error {
+	s.data = append(s.data, layout)
+	return nil
+}
+
+// testLayouts has an intercity layout with a first class coach and a standard
+// coach whose first seat is accessible.
+var testLayouts = &layoutTable{
+	Default: "intercity",
+	Layouts: map[string][]sectionConfig{
+		"intercity": {
+			{Section: "F", Rows: 1, SeatsPerRow: 2, Class: "First", FareFactor: 1.5},
+			{Section: "S", Rows: 2, SeatsPerRow: 2, AccessibleSeats: []seatConfig{{Row: 1, Number: 1}}},
+		},
+		"shuttle": {
+			{Section: "S", Rows: 10, SeatsPerRow: 4},
+		},
+	},
+}
+
+func TestLoadLayoutTable(t *testing.T) {
+	tests := []struct {
+		name        string
+		layouts     string
+		expectedErr bool
+	}{
+		{
+			name:    "Valid layouts",
+			layouts: `{"default": "intercity", "layouts": {"intercity": [{"section": "F", "rows": 2, "seats_per_row": 3, "class": "first", "accessible_seats": [{"row": 1, "number": 1}]}]}}`,
+		},
+		{
+			name:        "Malformed JSON",
+			layouts:     `{"default": `,
+			expectedErr: true,
+		},
+		{
+			name:        "Undefined default layout",
+			layouts:     `{"default": "regional", "layouts": {"intercity": [{"section": "A", "rows": 2, "seats_per_row": 3}]}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Layout without sections",
+			layouts:     `{"default": "intercity", "layouts": {"intercity": []}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Section listed twice",
+			layouts:     `{"default": "intercity", "layouts": {"intercity": [{"section": "A", "rows": 2, "seats_per_row": 3}, {"section": "A", "rows": 1, "seats_per_row": 3}]}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Accessible seat outside the section",
+			layouts:     `{"default": "intercity", "layouts": {"intercity": [{"section": "A", "rows": 2, "seats_per_row": 3, "accessible_seats": [{"row": 3, "number": 1}]}]}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "layouts.json")
+			if err := os.WriteFile(path, []byte(tc.layouts), 0o644); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			_, err := loadLayoutTable(path)
+			if tc.expectedErr != (err != nil) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateDepartureFromLayout(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	if len(departure.Sections) != 2 || departure.Sections[0].SeatClass != "first" || departure.Sections[1].SeatClass != defaultSeatClass {
+		t.Errorf("Expected the intercity sections with their classes, got %v", departure.Sections)
+	}
+	if departure.AvailableSeats["S"] != 4 || len(departure.Sections[1].AccessibleSeats) != 1 {
+		t.Errorf("Expected section S with 4 seats, one accessible, got %v", departure)
+	}
+
+	unknown := testSchedule("IC102", "2024-03-04", "09:30")
+	unknown.Sections = nil
+	unknown.Layout = "regional"
+	both := testSchedule("IC103", "2024-03-04", "09:30")
+	both.Layout = "intercity"
+	for _, req := range []*trainService.Departure{unknown, both} {
+		if _, err := server.CreateDeparture(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+
+	stream := &layoutStream{}
+	if err := server.ListLayouts(&trainService.ListLayoutsRequest{}, stream); err != nil {
+		t.Fatalf("ListLayouts failed: %v", err)
+	}
+	if len(stream.data) != 2 || stream.data[0].Name != "intercity" || stream.data[1].Name != "shuttle" {
+		t.Errorf("Expected both layouts by name, got %v", stream.data)
+	}
+}
+
+func TestAccessibleSeats(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts, duplicates: duplicateAllow}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	purchase := func(section string, accessible bool) (*trainService.Ticket, error) {
+		ticket := testJourney("test@example.com", departure.Id, section)
+		ticket.AccessibleSeat = accessible
+		return server.PurchaseTicket(ctx, ticket)
+	}
+
+	// Other passengers are seated around the accessible seat.
+	ticket, err := purchase("S", false)
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	if keyOf(ticket.Seat) != (seatKey{1, 2}) {
+		t.Errorf("Expected row 1 seat 2, got %s", seatLabel(ticket.Seat))
+	}
+	ticket, err = purchase("S", true)
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	if keyOf(ticket.Seat) != (seatKey{1, 1}) {
+		t.Errorf("Expected the accessible seat, got %s", seatLabel(ticket.Seat))
+	}
+	if _, err := purchase("S", true); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted with the accessible seat taken, got %v", err)
+	}
+	if _, err := purchase("F", true); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition in a section without accessible seats, got %v", err)
+	}
+
+	// A group leaves the accessible seat to the passenger who needs it.
+	if _, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: ticket.BookingReference}); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	group := testGroup("S", 3)
+	group.DepartureId, group.From, group.To = departure.Id, "LON", "PAR"
+	group.Passengers[2].AccessibleSeat = true
+	booking, err := server.PurchaseGroup(ctx, group)
+	if err != nil {
+		t.Fatalf("PurchaseGroup failed: %v", err)
+	}
+	if keyOf(booking.Tickets[2].Seat) != (seatKey{1, 1}) || booking.Tickets[0].Seat.Row != 2 || booking.Tickets[1].Seat.Row != 2 {
+		t.Errorf("Expected the accessible seat for the third passenger and row 2 for the others, got %v", booking.Tickets)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/iamir0nman/train/trainService"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// exchangeRates converts amounts from Base, the currency the pricing rules
+// are set in, to the other currencies fares can be quoted and charged in.
+// Rates are loaded from JSON with the -rates flag; see defaultRates for the
+// shape.
+type exchangeRates struct {
+	Base string `json:"base"`
+	// Rates holds the units of each currency one unit of Base buys.
+	Rates map[string]float64 `json:"rates"`
+}
+
+var defaultRates = exchangeRates{Base: "EUR"}
+
+// loadExchangeRates reads an exchange-rate table from a JSON file.
+func loadExchangeRates(path string) (*exchangeRates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r exchangeRates
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if err := r.validate(); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates in %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+func (r *exchangeRates) validate() error {
+	if !currencyCodePattern.MatchString(r.Base) {
+		return fmt.Errorf("base currency %q is not an ISO 4217 code", r.Base)
+	}
+	for code, rate := range r.Rates {
+		if !currencyCodePattern.MatchString(code) {
+			return fmt.Errorf("currency %q is not an ISO 4217 code", code)
+		}
+		if rate <= 0 {
+			return fmt.Errorf("currency %s has no positive rate", code)
+		}
+	}
+	return nil
+}
+
+// rate returns the units of currency one unit of the base currency buys, or
+// false if the currency is not supported.
+func (r *exchangeRates) rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok
+}
+
+// currency returns the supported currency code for a requested one, the base
+// currency if none was requested.
+func (r *exchangeRates) currency(requested string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(requested))
+	if code == "" {
+		return r.Base, nil
+	}
+	if _, ok := r.rate(code); !ok {
+		return "", invalidField("currency", fmt.Sprintf("currency %s is not supported", requested))
+	}
+	return code, nil
+}
+
+// fromBase returns amount of the base currency in currency, rounded to its
+// minor unit. The currency must be supported.
+func (r *exchangeRates) fromBase(amount float64, currency string) *trainService.Money {
+	rate, _ := r.rate(currency)
+	return &trainService.Money{CurrencyCode: currency, MinorUnits: toMinorUnits(amount*rate, currency)}
+}
+
+// convert returns m in currency. Both currencies must be supported.
+func (r *exchangeRates) convert(m *trainService.Money, currency string) *trainService.Money {
+	if m.CurrencyCode == currency {
+		return m
+	}
+	from, _ := r.rate(m.CurrencyCode)
+	return r.fromBase(majorUnits(m)/from, currency)
+}
+
+func toMinorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(trainService.CurrencyDigits(currency))))
+}
+
+func majorUnits(m *trainService.Money) float64 {
+	return float64(m.MinorUnits) / math.Pow10(trainService.CurrencyDigits(m.CurrencyCode))
+}
+
+// exchangeRates returns the exchange rates of the server, defaultRates unless
+// others were loaded.
+func (s *TrainServer) exchangeRates() *exchangeRates {
+	if s.rates != nil {
+		return s.rates
+	}
+	return &defaultRates
+}
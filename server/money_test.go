@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCurrencies(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateAllow,
+		rates: &exchangeRates{Base: "EUR", Rates: map[string]float64{"GBP": 0.85, "JPY": 160}},
+		now:   func() time.Time { return time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC) }}
+	ctx := context.Background()
+
+	// Adults pay 79.25 EUR from LON to PAR in section B.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	if _, err := server.CreatePromoCode(ctx, &trainService.PromoCode{Code: "FIVE", AmountOff: eur(500)}); err != nil {
+		t.Fatalf("CreatePromoCode failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		currency      string
+		passenger     trainService.PassengerType
+		promoCode     string
+		expectedPrice *trainService.Money
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "Pricing currency by default",
+			expectedPrice: eur(7925),
+		},
+		{
+			name:          "Pounds",
+			currency:      "gbp",
+			expectedPrice: &trainService.Money{CurrencyCode: "GBP", MinorUnits: 6736},
+		},
+		{
+			name:          "Yen have no minor unit",
+			currency:      "JPY",
+			passenger:     trainService.PassengerType_STUDENT,
+			expectedPrice: &trainService.Money{CurrencyCode: "JPY", MinorUnits: 9510},
+		},
+		{
+			name:          "Amount off converted",
+			currency:      "GBP",
+			promoCode:     "FIVE",
+			expectedPrice: &trainService.Money{CurrencyCode: "GBP", MinorUnits: 6311},
+		},
+		{
+			name:         "Unsupported currency",
+			currency:     "USD",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ticket := testJourney("currency@example.com", departure.Id, "B")
+			ticket.Currency, ticket.PassengerType, ticket.PromoCode = tc.currency, tc.passenger, tc.promoCode
+			ticket.User.StudentId = "S-1234"
+			quote, err := server.QuoteFare(ctx, ticket)
+
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+			if err != nil {
+				return
+			}
+			if !proto.Equal(quote.Price, tc.expectedPrice) {
+				t.Errorf("Expected a quote of %v, got %v", tc.expectedPrice, quote.Price)
+			}
+			for _, discount := range quote.Discounts {
+				if discount.Amount.CurrencyCode != tc.expectedPrice.CurrencyCode {
+					t.Errorf("Expected discounts in %s, got %v", tc.expectedPrice.CurrencyCode, discount)
+				}
+			}
+
+			ticket.QuoteToken = quote.QuoteToken
+			booked, err := server.PurchaseTicket(ctx, ticket)
+			if err != nil {
+				t.Fatalf("PurchaseTicket failed: %v", err)
+			}
+			defer server.CancelBooking(ctx, &trainService.BookingReference{Reference: booked.BookingReference})
+			if !proto.Equal(booked.Price, quote.Price) || !proto.Equal(booked.OriginalFare, quote.OriginalFare) {
+				t.Errorf("Expected to be charged the quote %v, got %v", quote, booked)
+			}
+		})
+	}
+
+	// A quote only holds in the currency it was given in.
+	quote, err := server.QuoteFare(ctx, &trainService.Ticket{DepartureId: departure.Id, From: "LON", To: "PAR", Section: "B", Currency: "GBP"})
+	if err != nil {
+		t.Fatalf("QuoteFare failed: %v", err)
+	}
+	ticket := testJourney("currency@example.com", departure.Id, "B")
+	ticket.QuoteToken = quote.QuoteToken
+	if _, err := server.PurchaseTicket(ctx, ticket); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a token quoted in another currency, got %v", err)
+	}
+}
+
+func TestLoadExchangeRates(t *testing.T) {
+	tests := []struct {
+		name        string
+		rates       string
+		expectedErr bool
+	}{
+		{
+			name:  "Valid rates",
+			rates: `{"base": "EUR", "rates": {"GBP": 0.85, "USD": 1.08}}`,
+		},
+		{
+			name:        "Missing base",
+			rates:       `{"rates": {"GBP": 0.85}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Invalid currency code",
+			rates:       `{"base": "EUR", "rates": {"pound": 0.85}}`,
+			expectedErr: true,
+		},
+		{
+			name:        "Zero rate",
+			rates:       `{"base": "EUR", "rates": {"GBP": 0}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rates.json")
+			if err := os.WriteFile(path, []byte(tc.rates), 0o644); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			_, err := loadExchangeRates(path)
+			if tc.expectedErr != (err != nil) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// compensationPolicy sets what a passenger bumped from an overbooked
+// departure is owed: Percent of the amount paid for the ticket, and at least
+// Minimum, in the base currency.
+type compensationPolicy struct {
+	Percent float64 `json:"percent"`
+	Minimum float64 `json:"minimum"`
+}
+
+func (p compensationPolicy) validate() error {
+	if p.Percent < 0 || p.Minimum < 0 {
+		return fmt.Errorf("compensation %+v cannot be negative", p)
+	}
+	return nil
+}
+
+// compensationFor returns the compensation owed for bumping ticket, converted
+// from the base currency with rates where needed.
+func (p compensationPolicy) compensationFor(ticket *trainService.Ticket, rates *exchangeRates) *trainService.Money {
+	paid := ticket.AmountPaid
+	if paid == nil {
+		paid = ticket.Price
+	}
+	amount := int64(math.Round(float64(paid.MinorUnits) * p.Percent / 100))
+	return &trainService.Money{
+		CurrencyCode: paid.CurrencyCode,
+		MinorUnits:   max(amount, rates.fromBase(p.Minimum, paid.CurrencyCode).MinorUnits),
+	}
+}
+
+// overbookingLimit returns how many tickets section may sell beyond its seats.
+func overbookingLimit(section *trainService.SectionCapacity) int {
+	return int(section.Rows*section.SeatsPerRow*section.OverbookingPercent) / 100
+}
+
+// canOverbook reports whether req can be sold without a seat in a section of
+// departure that has free seats left on the leg travelled, counting tickets
+// already sold without one. Passengers who ask for a particular or an
+// accessible seat are only sold one that is free now.
+func canOverbook(departure *trainService.Departure, req *trainService.Ticket, free int) bool {
+	section, ok := findSection(departure, req.Section)
+	if !ok || req.Seat != nil || req.AccessibleSeat {
+		return false
+	}
+	return free > -overbookingLimit(section)
+}
+
+func checkInClosed(departure string) error {
+	return preconditionFailed("CHECK_IN_CLOSED", "departure:"+departure,
+		fmt.Sprintf("check-in for departure %s has closed", departure))
+}
+
+// checkInOpen checks that tickets on departure can still be sold and changed.
+func checkInOpen(departure *trainService.Departure) error {
+	if departure.CheckInClosed {
+		return checkInClosed(departure.Id)
+	}
+	return nil
+}
+
+// CheckIn records that the passenger of a ticket is travelling. A ticket sold
+// without a seat gets a free one if there is any; otherwise it is seated when
+// check-in closes, if a passenger who did not check in leaves one.
+func (s *TrainServer) CheckIn(ctx context.Context, req *trainService.BookingReference) (*trainService.Ticket, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("reference field is empty", field{"reference", req.Reference}); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, err := s.store.FindTicket(req.Reference)
+	if err != nil {
+		return nil, bookingNotFound(req.Reference)
+	}
+	departure, err := s.store.FindDeparture(ticket.DepartureId)
+	if err != nil {
+		return nil, internalError("failed to load departure", err)
+	}
+	if err := checkInOpen(departure); err != nil {
+		return nil, err
+	}
+	if ticket.CheckedIn {
+		return ticket, nil
+	}
+
+	updated := proto.Clone(ticket).(*trainService.Ticket)
+	updated.CheckedIn = true
+	if updated.Seat == nil {
+		// A seat given up by a cancellation goes to the first to check in.
+		if seat, err := s.allocateSeat(departure, ticket.Section, ticketLeg(departure, ticket), nil, ticket.AccessibleSeat, ""); err == nil {
+			updated.Seat = seat
+		}
+	}
+	if err := s.store.UpdateTicket(updated); err != nil {
+		return nil, internalError("failed to update ticket", err)
+	}
+	return updated, nil
+}
+
+// CloseCheckIn stops sales on a departure and settles who travels. Passengers
+// who did not check in are no-shows and give up their seats. Checked-in
+// passengers without a seat are then seated in booking order, and those left
+// over, the last sold, are bumped: each is rebooked in booking order onto the
+// earliest later departure with a seat on their journey in a section selling
+// their fare class, or cancelled and refunded in full if there is none, and
+// is owed compensation either way. The outcome is recorded on the departure
+// for the no-show statistics.
+func (s *TrainServer) CloseCheckIn(ctx context.Context, req *trainService.CloseCheckInRequest) (*trainService.CheckInReport, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+
+	report, cancelled, err := s.closeCheckIn(req.DepartureId)
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range cancelled {
+		s.refund(ctx, ticket)
+	}
+	return report, nil
+}
+
+// closeCheckIn settles the departure with the given ID, returning the report
+// and the bumped tickets cancelled for want of a later seat, to be refunded.
+func (s *TrainServer) closeCheckIn(id string) (*trainService.CheckInReport, []*trainService.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	departure, err := s.findDeparture(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkInOpen(departure); err != nil {
+		return nil, nil, err
+	}
+	if err := s.clearSales(departure.Id); err != nil {
+		return nil, nil, err
+	}
+
+	closed := proto.Clone(departure).(*trainService.Departure)
+	closed.CheckInClosed = true
+	var tickets []*trainService.Ticket
+	for _, ticket := range departureTickets(s.store.Tickets(), departure.Id) {
+		tickets = append(tickets, proto.Clone(ticket).(*trainService.Ticket))
+	}
+	var bumped []*trainService.Ticket
+	for _, section := range departure.Sections {
+		summary, left := seatCheckedIn(departure, section, tickets)
+		closed.CheckIn = append(closed.CheckIn, summary)
+		bumped = append(bumped, left...)
+	}
+	// Section by section is not booking order.
+	order := map[string]int{}
+	for i, ticket := range tickets {
+		order[ticket.BookingReference] = i
+	}
+	sort.Slice(bumped, func(i, j int) bool { return order[bumped[i].BookingReference] < order[bumped[j].BookingReference] })
+
+	report := &trainService.CheckInReport{}
+	var moved, cancelled []*trainService.Ticket
+	var cancelledReferences []string
+	for _, ticket := range bumped {
+		ticket.Compensation = &trainService.Compensation{
+			Amount:     s.fareTable().Compensation.compensationFor(ticket, s.exchangeRates()),
+			BumpedFrom: departure.Id,
+		}
+		summary := sectionSummary(closed, ticket.Section)
+		summary.Bumped++
+		if s.rebook(departure, ticket, moved) {
+			summary.Rebooked++
+			ticket.Compensation.Reason = fmt.Sprintf("bumped from overbooked departure %s and rebooked onto %s", departure.Id, ticket.DepartureId)
+			moved = append(moved, ticket)
+		} else {
+			ticket.Compensation.Reason = fmt.Sprintf("bumped from overbooked departure %s with no later departure to rebook onto", departure.Id)
+			if ticket.PaymentReference != "" && ticket.AmountPaid != nil {
+				ticket.Refund = &trainService.Refund{
+					Amount: proto.Clone(ticket.AmountPaid).(*trainService.Money),
+					Fee:    &trainService.Money{CurrencyCode: ticket.AmountPaid.CurrencyCode},
+					Status: trainService.RefundStatus_REFUNDED,
+					Reason: "bumped from an overbooked departure",
+				}
+			}
+			cancelled = append(cancelled, ticket)
+			cancelledReferences = append(cancelledReferences, ticket.BookingReference)
+		}
+		report.Bumped = append(report.Bumped, ticket)
+	}
+
+	// Cancelled tickets leave the store; every other ticket is saved as
+	// settled, on this departure or the one it was rebooked onto.
+	var settled []*trainService.Ticket
+	for _, ticket := range tickets {
+		if !containsString(cancelledReferences, ticket.BookingReference) {
+			settled = append(settled, ticket)
+		}
+	}
+	if err := s.store.SettleDeparture(closed, settled, cancelledReferences); err != nil {
+		return nil, nil, internalError("failed to close check-in", err)
+	}
+	for _, ticket := range report.Bumped {
+		s.publishAvailability(ticket.DepartureId)
+	}
+	s.publishAvailability(closed.Id)
+	report.Departure = s.withAvailability(closed)
+	return report, cancelled, nil
+}
+
+// clearSales releases the seat holds on a departure whose check-in closes and
+// takes its waitlist entries off the queue. Holds being paid for cannot be
+// released, so check-in cannot close until their payment is settled. Callers
+// must hold s.mu.
+func (s *TrainServer) clearSales(departure string) error {
+	for _, hold := range s.store.Holds() {
+		if hold.Ticket.DepartureId == departure && s.paying[hold.Token] {
+			return status.Error(codes.Aborted, "a payment for a seat on this departure is being taken, try again")
+		}
+	}
+	for _, entry := range s.store.Waitlist() {
+		if entry.Ticket.DepartureId != departure {
+			continue
+		}
+		if _, err := s.store.RemoveWaitlistEntry(entry.Id); err != nil {
+			return internalError("failed to remove waitlist entry", err)
+		}
+	}
+	for _, hold := range s.store.Holds() {
+		if hold.Ticket.DepartureId != departure {
+			continue
+		}
+		if _, err := s.store.RemoveHold(hold.Token); err != nil {
+			return internalError("failed to release seat hold", err)
+		}
+	}
+	return nil
+}
+
+// seatCheckedIn marks the tickets in a section of departure that did not check
+// in as no-shows, freeing their seats, and seats the checked-in tickets sold
+// without one in the order given. It returns the section's check-in summary
+// and the checked-in tickets left without a seat.
+func seatCheckedIn(departure *trainService.Departure, section *trainService.SectionCapacity, tickets []*trainService.Ticket) (*trainService.SectionCheckIn, []*trainService.Ticket) {
+	summary := &trainService.SectionCheckIn{Section: section.Section, Seats: section.Rows * section.SeatsPerRow}
+	var inSection, unseated []*trainService.Ticket
+	for _, ticket := range tickets {
+		if ticket.Section != section.Section {
+			continue
+		}
+		inSection = append(inSection, ticket)
+		summary.Booked++
+		switch {
+		case !ticket.CheckedIn:
+			summary.NoShows++
+			ticket.NoShow = true
+			ticket.Seat = nil
+		case ticket.Seat == nil:
+			summary.CheckedIn++
+			unseated = append(unseated, ticket)
+		default:
+			summary.CheckedIn++
+		}
+	}
+
+	layout, _ := sectionLayout(departure, section.Section)
+	var left []*trainService.Ticket
+	for _, ticket := range unseated {
+		taken := takenBy(departure, inSection, section.Section, ticketLeg(departure, ticket), "")
+		seat := firstFreeSeat(layout, taken, ticket.AccessibleSeat)
+		if seat == nil && !ticket.AccessibleSeat {
+			seat = firstFreeSeat(layout, taken, true)
+		}
+		if seat == nil {
+			left = append(left, ticket)
+			continue
+		}
+		ticket.Seat = seat
+	}
+	return summary, left
+}
+
+func sectionSummary(departure *trainService.Departure, section string) *trainService.SectionCheckIn {
+	for _, summary := range departure.CheckIn {
+		if summary.Section == section {
+			return summary
+		}
+	}
+	return &trainService.SectionCheckIn{}
+}
+
+// rebook moves ticket, bumped from departure, to a seat on the earliest later
+// departure still selling tickets that serves its journey and has a section
+// selling its fare class with a seat free, trying the ticket's own section
+// first. Tickets already rebooked in moved count as taking their seats. It
+// reports whether a seat was found. Callers must hold s.mu.
+func (s *TrainServer) rebook(departure *trainService.Departure, ticket *trainService.Ticket, moved []*trainService.Ticket) bool {
+	bumpedAt, ok := departsAt(departure)
+	if !ok {
+		return false
+	}
+	var candidates []*trainService.Departure
+	departures := map[string]time.Time{}
+	for _, candidate := range s.store.Departures() {
+		at, ok := departsAt(candidate)
+		if !ok || !at.After(bumpedAt) || candidate.CheckInClosed {
+			continue
+		}
+		if _, err := legOf(candidate, ticket.From, ticket.To); err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate)
+		departures[candidate.Id] = at
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return departures[candidates[i].Id].Before(departures[candidates[j].Id]) })
+
+	for _, candidate := range candidates {
+		travelled, _ := legOf(candidate, ticket.From, ticket.To)
+		sections := append([]*trainService.SectionCapacity(nil), candidate.Sections...)
+		sort.SliceStable(sections, func(i, j int) bool {
+			return sections[i].Section == ticket.Section && sections[j].Section != ticket.Section
+		})
+		for _, section := range sections {
+			if _, _, err := s.fareTable().fareClass(ticket.FareClass, section); err != nil {
+				continue
+			}
+			seated := s.seatedTickets(candidate.Id)
+			free := s.legSeats(candidate, section.Section, travelled, nil)
+			for _, other := range moved {
+				if other.DepartureId == candidate.Id {
+					seated = append(seated, other)
+					if other.Section == section.Section && ticketLeg(candidate, other).overlaps(travelled) {
+						free--
+					}
+				}
+			}
+			if free <= 0 {
+				continue
+			}
+			layout, _ := sectionLayout(candidate, section.Section)
+			taken := takenBy(candidate, seated, section.Section, travelled, "")
+			seat := firstFreeSeat(layout, taken, ticket.AccessibleSeat)
+			if seat == nil && !ticket.AccessibleSeat {
+				seat = firstFreeSeat(layout, taken, true)
+			}
+			if seat == nil {
+				continue
+			}
+			ticket.DepartureId = candidate.Id
+			ticket.Section = section.Section
+			ticket.Seat = seat
+			ticket.CheckedIn = false
+			return true
+		}
+	}
+	return false
+}
+
+// GetNoShowStats sums up check-in on the departures whose check-in has
+// closed, so sections can be overbooked by about as many tickets as go
+// unused.
+func (s *TrainServer) GetNoShowStats(ctx context.Context, req *trainService.NoShowStatsRequest) (*trainService.NoShowStats, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := &trainService.NoShowStats{}
+	for _, departure := range s.store.Departures() {
+		if !departure.CheckInClosed || (req.TrainNumber != "" && departure.TrainNumber != req.TrainNumber) {
+			continue
+		}
+		stats.Departures++
+		for _, summary := range departure.CheckIn {
+			if req.Section != "" && summary.Section != req.Section {
+				continue
+			}
+			stats.Booked += summary.Booked
+			stats.CheckedIn += summary.CheckedIn
+			stats.NoShows += summary.NoShows
+			stats.Bumped += summary.Bumped
+		}
+	}
+	if stats.Booked > 0 {
+		stats.NoShowRate = float32(stats.NoShows) / float32(stats.Booked)
+	}
+	return stats, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// overbookedDeparture creates a departure whose section B, with four seats,
+// sells two tickets beyond them.
+func overbookedDeparture(t *testing.T, server *TrainServer, train, departureTime string) *trainService.Departure {
+	t.Helper()
+	req := testSchedule(train, "2024-03-04", departureTime)
+	req.Sections[1].OverbookingPercent = 50
+	return createDeparture(t, server, req)
+}
+
+func TestOverbooking(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), payments: newFakePaymentProvider(paymentApprove)}
+	ctx := context.Background()
+
+	departure := overbookedDeparture(t, server, "IC101", "09:30")
+	later := createDeparture(t, server, testSchedule("IC103", "2024-03-04", "12:00"))
+	purchase := func(i int) (*trainService.Ticket, error) {
+		ticket := testJourney(fmt.Sprintf("passenger%d@example.com", i), departure.Id, "B")
+		return server.PurchaseTicket(ctx, ticket)
+	}
+
+	// Four seated tickets, then two sold over capacity without a seat.
+	var booked []*trainService.Ticket
+	for i := 0; i < 6; i++ {
+		ticket, err := purchase(i)
+		if err != nil {
+			t.Fatalf("PurchaseTicket %d failed: %v", i, err)
+		}
+		if seated := ticket.Seat != nil; seated != (i < 4) {
+			t.Errorf("Expected ticket %d seated: %v, got %v", i, i < 4, ticket.Seat)
+		}
+		booked = append(booked, ticket)
+	}
+	if _, err := purchase(6); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted past the overbooking limit, got %v", err)
+	}
+	if free := server.withAvailability(departure).AvailableSeats["B"]; free != 0 {
+		t.Errorf("Expected no seats shown free, got %d", free)
+	}
+
+	// Passenger 3 does not turn up, so passenger 4 takes their seat and
+	// passenger 5, the last sold, is bumped.
+	for _, i := range []int{0, 1, 2, 4, 5} {
+		ticket, err := server.CheckIn(ctx, &trainService.BookingReference{Reference: booked[i].BookingReference})
+		if err != nil {
+			t.Fatalf("CheckIn failed: %v", err)
+		}
+		if !ticket.CheckedIn || (i >= 4 && ticket.Seat != nil) {
+			t.Errorf("Expected passenger %d checked in without a new seat, got %v", i, ticket)
+		}
+	}
+	report, err := server.CloseCheckIn(ctx, &trainService.CloseCheckInRequest{DepartureId: departure.Id})
+	if err != nil {
+		t.Fatalf("CloseCheckIn failed: %v", err)
+	}
+	expected := &trainService.SectionCheckIn{Section: "B", Seats: 4, Booked: 6, CheckedIn: 5, NoShows: 1, Bumped: 1, Rebooked: 1}
+	if !report.Departure.CheckInClosed || !proto.Equal(sectionSummary(report.Departure, "B"), expected) {
+		t.Errorf("Expected %v, got %v", expected, report.Departure.CheckIn)
+	}
+	if noShow, _ := server.store.FindTicket(booked[3].BookingReference); !noShow.NoShow || noShow.Seat != nil {
+		t.Errorf("Expected passenger 3 a no-show without a seat, got %v", noShow)
+	}
+	if seated, _ := server.store.FindTicket(booked[4].BookingReference); !proto.Equal(seated.Seat, booked[3].Seat) {
+		t.Errorf("Expected passenger 4 in the no-show's seat %v, got %v", booked[3].Seat, seated.Seat)
+	}
+	if len(report.Bumped) != 1 {
+		t.Fatalf("Expected one passenger bumped, got %v", report.Bumped)
+	}
+	rebooked := report.Bumped[0]
+	if rebooked.BookingReference != booked[5].BookingReference || rebooked.DepartureId != later.Id || rebooked.Section != "B" || rebooked.Seat == nil {
+		t.Errorf("Expected passenger 5 rebooked onto %s, got %v", later.Id, rebooked)
+	}
+	compensation := max((booked[5].AmountPaid.MinorUnits+1)/2, 2500)
+	if rebooked.Compensation == nil || rebooked.Compensation.BumpedFrom != departure.Id || !proto.Equal(rebooked.Compensation.Amount, eur(compensation)) {
+		t.Errorf("Expected compensation of %d recorded, got %v", compensation, rebooked.Compensation)
+	}
+	if stored, _ := server.store.FindTicket(rebooked.BookingReference); !proto.Equal(stored, rebooked) || server.store.SeatCount(later.Id, "B") != 3 {
+		t.Errorf("Expected the rebooked ticket to take a seat on %s, got %v", later.Id, stored)
+	}
+
+	// The closed departure no longer sells or checks in.
+	if _, err := purchase(7); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition buying on a closed departure, got %v", err)
+	}
+	if _, err := server.CheckIn(ctx, &trainService.BookingReference{Reference: booked[0].BookingReference}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition checking in after check-in closed, got %v", err)
+	}
+	if _, err := server.CloseCheckIn(ctx, &trainService.CloseCheckInRequest{DepartureId: departure.Id}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition closing check-in twice, got %v", err)
+	}
+	resize := &trainService.ResizeSectionRequest{DepartureId: departure.Id, Section: "B", Rows: 9, SeatsPerRow: 9}
+	if _, err := server.ResizeSection(ctx, resize); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition resizing a section after check-in closed, got %v", err)
+	}
+
+	stats, err := server.GetNoShowStats(ctx, &trainService.NoShowStatsRequest{TrainNumber: "IC101"})
+	if err != nil {
+		t.Fatalf("GetNoShowStats failed: %v", err)
+	}
+	expectedStats := &trainService.NoShowStats{Departures: 1, Booked: 6, CheckedIn: 5, NoShows: 1, NoShowRate: float32(1) / 6, Bumped: 1}
+	if !proto.Equal(stats, expectedStats) {
+		t.Errorf("Expected %v, got %v", expectedStats, stats)
+	}
+}
+
+func TestCloseCheckInRefundsBumped(t *testing.T) {
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: newRouteStore(), payments: payments}
+	ctx := context.Background()
+
+	departure := overbookedDeparture(t, server, "IC101", "09:30")
+	var booked []*trainService.Ticket
+	for i := 0; i < 6; i++ {
+		ticket := testJourney(fmt.Sprintf("passenger%d@example.com", i), departure.Id, "B")
+		ticket, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		if _, err := server.CheckIn(ctx, &trainService.BookingReference{Reference: ticket.BookingReference}); err != nil {
+			t.Fatalf("CheckIn failed: %v", err)
+		}
+		booked = append(booked, ticket)
+	}
+
+	// Everyone turned up and no later departure serves the journey.
+	report, err := server.CloseCheckIn(ctx, &trainService.CloseCheckInRequest{DepartureId: departure.Id})
+	if err != nil {
+		t.Fatalf("CloseCheckIn failed: %v", err)
+	}
+	if len(report.Bumped) != 2 {
+		t.Fatalf("Expected the two overbooked passengers bumped, got %v", report.Bumped)
+	}
+	for i, ticket := range report.Bumped {
+		if ticket.BookingReference != booked[4+i].BookingReference || ticket.Compensation == nil || ticket.Refund.Status != trainService.RefundStatus_REFUNDED {
+			t.Errorf("Expected passenger %d cancelled with compensation, got %v", 4+i, ticket)
+		}
+		if !proto.Equal(payments.remaining(ticket.PaymentReference), eur(0)) {
+			t.Errorf("Expected a full refund, got %v left", payments.remaining(ticket.PaymentReference))
+		}
+		if _, err := server.store.FindTicket(ticket.BookingReference); err == nil {
+			t.Errorf("Expected ticket %s cancelled", ticket.BookingReference)
+		}
+	}
+}
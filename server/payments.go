@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// defaultPaymentTimeout is how long the payment provider is given to answer a
+// charge when the server has no other timeout configured.
+const defaultPaymentTimeout = 30 * time.Second
+
+var (
+	errPaymentDeclined = errors.New("payment declined")
+	errPaymentNotFound = errors.New("payment not found")
+)
+
+// PaymentProvider takes payment for tickets.
+type PaymentProvider interface {
+	// Charge takes amount from the passenger with the given email and returns
+	// the provider's payment reference. A charge made again with the same
+	// non-empty key returns the first payment instead of taking another. It
+	// returns errPaymentDeclined if the payment is refused, and ctx.Err() if
+	// ctx is done first.
+	Charge(ctx context.Context, key, email string, amount *trainService.Money) (string, error)
+	// FindCharge returns the reference of the payment taken by the charge with
+	// the given key, or errPaymentNotFound if it did not go through.
+	FindCharge(ctx context.Context, key string) (string, error)
+	// Refund gives amount of the payment with the given reference back.
+	Refund(ctx context.Context, reference string, amount *trainService.Money) error
+}
+
+// paymentOutcome is how the fake payment provider answers charges.
+type paymentOutcome int
+
+const (
+	paymentApprove paymentOutcome = iota
+	paymentDecline
+	// paymentTimeout never answers, so charges run until the context is done.
+	paymentTimeout
+)
+
+func parsePaymentOutcome(name string) (paymentOutcome, error) {
+	switch name {
+	case "approve":
+		return paymentApprove, nil
+	case "decline":
+		return paymentDecline, nil
+	case "timeout":
+		return paymentTimeout, nil
+	}
+	return 0, fmt.Errorf("unknown payment outcome %q, expected approve, decline or timeout", name)
+}
+
+// fakePaymentProvider is a local PaymentProvider that answers every charge
+// with the same outcome and keeps the payments it approved in memory.
+type fakePaymentProvider struct {
+	mu       sync.Mutex
+	outcome  paymentOutcome
+	payments map[string]*trainService.Money // refundable amount by reference
+	charges  map[string]string              // payment reference by charge key
+}
+
+func newFakePaymentProvider(outcome paymentOutcome) *fakePaymentProvider {
+	return &fakePaymentProvider{outcome: outcome, payments: map[string]*trainService.Money{}, charges: map[string]string{}}
+}
+
+// defaultPayments approves every charge for servers without a provider.
+var defaultPayments = newFakePaymentProvider(paymentApprove)
+
+func (f *fakePaymentProvider) Charge(ctx context.Context, key, email string, amount *trainService.Money) (string, error) {
+	f.mu.Lock()
+	outcome := f.outcome
+	reference, charged := f.charges[key]
+	f.mu.Unlock()
+	if charged {
+		return reference, nil
+	}
+
+	switch outcome {
+	case paymentDecline:
+		return "", errPaymentDeclined
+	case paymentTimeout:
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	reference = "PAY-" + hex.EncodeToString(buf)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.payments[reference] = proto.Clone(amount).(*trainService.Money)
+	if key != "" {
+		f.charges[key] = reference
+	}
+	return reference, nil
+}
+
+func (f *fakePaymentProvider) FindCharge(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	reference, ok := f.charges[key]
+	if !ok || key == "" {
+		return "", errPaymentNotFound
+	}
+	return reference, nil
+}
+
+func (f *fakePaymentProvider) Refund(ctx context.Context, reference string, amount *trainService.Money) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	paid, ok := f.payments[reference]
+	if !ok {
+		return errPaymentNotFound
+	}
+	if amount.CurrencyCode != paid.CurrencyCode || amount.MinorUnits > paid.MinorUnits {
+		return fmt.Errorf("cannot refund %s of a payment of %s", trainService.FormatMoney(amount), trainService.FormatMoney(paid))
+	}
+	paid.MinorUnits -= amount.MinorUnits
+	return nil
+}
+
+// remaining returns the amount of the payment with the given reference that
+// has not been refunded, or nil if there is no such payment.
+func (f *fakePaymentProvider) remaining(reference string) *trainService.Money {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	paid, ok := f.payments[reference]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(paid).(*trainService.Money)
+}
+
+func (s *TrainServer) paymentProvider() PaymentProvider {
+	if s.payments == nil {
+		return defaultPayments
+	}
+	return s.payments
+}
+
+// charge takes payment of amount from the user with the given email, giving
+// the provider at most the payment timeout to answer. key names the charge so
+// it can be found again, and may be empty. Callers must not hold s.mu.
+func (s *TrainServer) charge(ctx context.Context, key, email string, amount *trainService.Money) (string, error) {
+	timeout := s.paymentTimeout
+	if timeout <= 0 {
+		timeout = defaultPaymentTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return s.paymentProvider().Charge(ctx, key, email, amount)
+}
+
+// paymentFailed reports a charge that did not go through.
+func paymentFailed(err error) error {
+	switch {
+	case errors.Is(err, errPaymentDeclined):
+		return preconditionFailed("PAYMENT_DECLINED", "payment", "payment was declined, no ticket was booked")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.Unavailable, "payment provider did not answer in time, no ticket was booked")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "payment was cancelled, no ticket was booked")
+	}
+	return status.Errorf(codes.Unavailable, "payment failed, no ticket was booked: %v", err)
+}
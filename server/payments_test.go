@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPurchasePayments(t *testing.T) {
+	tests := []struct {
+		name         string
+		outcome      paymentOutcome
+		expectedCode codes.Code
+	}{
+		{name: "Approved payment books the ticket", outcome: paymentApprove, expectedCode: codes.OK},
+		{name: "Declined payment books nothing", outcome: paymentDecline, expectedCode: codes.FailedPrecondition},
+		{name: "Payment timeout books nothing", outcome: paymentTimeout, expectedCode: codes.Unavailable},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(testLayout, nil)
+			payments := newFakePaymentProvider(tc.outcome)
+			server := &TrainServer{store: store, payments: payments, paymentTimeout: 10 * time.Millisecond}
+			seats := store.SeatCount("", "A")
+
+			ticket, err := server.PurchaseTicket(context.Background(), testTicket("deepak@example.com", "A"))
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got %v", tc.expectedCode, err)
+			}
+			if tc.expectedCode != codes.OK {
+				if len(store.Tickets()) != 0 || len(store.Holds()) != 0 || store.SeatCount("", "A") != seats {
+					t.Errorf("Expected the seat back on sale, got tickets %v, holds %v and %d free seats", store.Tickets(), store.Holds(), store.SeatCount("", "A"))
+				}
+				return
+			}
+			if ticket.PaymentReference == "" || !proto.Equal(payments.remaining(ticket.PaymentReference), ticket.Price) {
+				t.Errorf("Expected the ticket to record a payment of its price, got %v", ticket)
+			}
+			if len(store.Holds()) != 0 || store.SeatCount("", "A") != seats-1 {
+				t.Errorf("Expected one seat booked, got holds %v and %d free seats", store.Holds(), store.SeatCount("", "A"))
+			}
+		})
+	}
+}
+
+// hookedPayments runs before ahead of the next charge it is asked for.
+type hookedPayments struct {
+	*fakePaymentProvider
+	before func()
+}
+
+func (h *hookedPayments) Charge(ctx context.Context, key, email string, amount *trainService.Money) (string, error) {
+	if before := h.before; before != nil {
+		h.before = nil
+		before()
+	}
+	return h.fakePaymentProvider.Charge(ctx, key, email, amount)
+}
+
+func TestConfirmBookingPayments(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(testLayout, nil)
+	payments := newFakePaymentProvider(paymentDecline)
+	server := &TrainServer{store: store, payments: payments, duplicates: duplicateReject, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	hold, err := server.HoldSeat(ctx, testTicket("deepak@example.com", "A"))
+	if err != nil {
+		t.Fatalf("HoldSeat failed: %v", err)
+	}
+	confirm := &trainService.ConfirmBookingRequest{HoldToken: hold.Token}
+	if _, err := server.ConfirmBooking(ctx, confirm); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a declined payment, got %v", err)
+	}
+	if _, err := store.FindHold(hold.Token); err != nil {
+		t.Errorf("Expected a declined payment to keep the hold, got %v", err)
+	}
+
+	// A hold whose payment is being taken is neither confirmed twice nor
+	// reaped.
+	server.startPayment(hold.Token)
+	if _, err := server.ConfirmBooking(ctx, confirm); status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted while the payment is taken, got %v", err)
+	}
+	now = now.Add(defaultHoldTTL)
+	if released, err := server.releaseExpiredHolds(); err != nil || released != 0 {
+		t.Errorf("Expected the hold being paid for to be kept, got %d released (%v)", released, err)
+	}
+	delete(server.paying, hold.Token)
+	now = now.Add(-defaultHoldTTL)
+
+	payments.outcome = paymentApprove
+	ticket, err := server.ConfirmBooking(ctx, confirm)
+	if err != nil {
+		t.Fatalf("ConfirmBooking failed: %v", err)
+	}
+	if ticket.PaymentReference == "" || !proto.Equal(payments.remaining(ticket.PaymentReference), hold.Ticket.Price) {
+		t.Errorf("Expected the ticket to record a payment of the held price, got %v", ticket)
+	}
+}
+
+func TestPaymentRefundedWhenBookingFails(t *testing.T) {
+	store := newTestStore(testLayout, nil)
+	payments := &hookedPayments{fakePaymentProvider: newFakePaymentProvider(paymentApprove)}
+	server := &TrainServer{store: store, payments: payments, duplicates: duplicateReject}
+	ctx := context.Background()
+
+	hold, err := server.HoldSeat(ctx, testTicket("deepak@example.com", "A"))
+	if err != nil {
+		t.Fatalf("HoldSeat failed: %v", err)
+	}
+	// The user buys a ticket while the hold is being paid for.
+	payments.before = func() {
+		if _, err := server.PurchaseTicket(ctx, testTicket("deepak@example.com", "B")); err != nil {
+			t.Errorf("PurchaseTicket failed: %v", err)
+		}
+	}
+	if _, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: hold.Token}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("Expected AlreadyExists, got %v", err)
+	}
+
+	refunded := 0
+	for reference, paid := range payments.payments {
+		if reference != store.Tickets()[0].PaymentReference && paid.MinorUnits == 0 {
+			refunded++
+		}
+	}
+	if len(payments.payments) != 2 || refunded != 1 {
+		t.Errorf("Expected the payment for the hold to be refunded, got %v", payments.payments)
+	}
+}
+
+func TestSettleInterruptedCharges(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestFileStore(t, dir)
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: store, payments: payments, duplicates: duplicateReject}
+	ctx := context.Background()
+
+	// Each hold has its charge key on record; the server stops before the
+	// payments taken for two of them are booked.
+	charged := map[string]string{}
+	holds := map[string]*trainService.SeatHold{}
+	for _, email := range []string{"paid@example.com", "unpaid@example.com", "booked@example.com"} {
+		hold, err := server.HoldSeat(ctx, testTicket(email, "A"))
+		if err != nil {
+			t.Fatalf("HoldSeat failed: %v", err)
+		}
+		if hold, err = server.recordChargeKey(hold); err != nil {
+			t.Fatalf("recordChargeKey failed: %v", err)
+		}
+		holds[email] = hold
+		if email == "unpaid@example.com" {
+			continue
+		}
+		if charged[email], err = payments.Charge(ctx, hold.ChargeKey, email, hold.Ticket.Price); err != nil {
+			t.Fatalf("Charge failed: %v", err)
+		}
+		if again, err := payments.Charge(ctx, hold.ChargeKey, email, hold.Ticket.Price); err != nil || again != charged[email] {
+			t.Fatalf("Expected a repeated charge to return payment %s, got %s (err %v)", charged[email], again, err)
+		}
+	}
+	// This passenger bought another ticket, so their hold can no longer be
+	// booked.
+	if _, err := server.PurchaseTicket(ctx, testTicket("booked@example.com", "B")); err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+
+	reopened, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	restarted := &TrainServer{store: reopened, payments: payments, duplicates: duplicateReject}
+	restarted.settleInterruptedCharges(ctx)
+
+	paid, err := restarted.firstUserTicket("paid@example.com")
+	if err != nil || paid.PaymentReference != charged["paid@example.com"] || !proto.Equal(paid.Seat, holds["paid@example.com"].Ticket.Seat) {
+		t.Errorf("Expected the paid hold booked with payment %s, got %v (err %v)", charged["paid@example.com"], paid, err)
+	}
+	if _, err := reopened.FindHold(holds["unpaid@example.com"].Token); err != nil {
+		t.Errorf("Expected the unpaid hold kept, got %v", err)
+	}
+	if _, err := restarted.firstUserTicket("unpaid@example.com"); err == nil {
+		t.Error("Expected no ticket booked without a payment")
+	}
+	if _, err := reopened.FindHold(holds["booked@example.com"].Token); err == nil {
+		t.Error("Expected the hold that could not be booked released")
+	}
+	if remaining := payments.remaining(charged["booked@example.com"]); !proto.Equal(remaining, eur(0)) {
+		t.Errorf("Expected the payment for the hold that could not be booked refunded, got %v left", remaining)
+	}
+}
+
+// unreachableLookups is a payment provider that cannot look charges up while
+// down is set.
+type unreachableLookups struct {
+	*fakePaymentProvider
+	down bool
+}
+
+func (u *unreachableLookups) FindCharge(ctx context.Context, key string) (string, error) {
+	if u.down {
+		return "", status.Error(codes.Unavailable, "payment provider unreachable")
+	}
+	return u.fakePaymentProvider.FindCharge(ctx, key)
+}
+
+func TestSettleInterruptedChargeLookupFailure(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	payments := &unreachableLookups{fakePaymentProvider: newFakePaymentProvider(paymentApprove), down: true}
+	server := &TrainServer{store: newTestStore(testLayout, nil), payments: payments, duplicates: duplicateReject, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	// Both holds were charged before the server stopped.
+	holds := map[string]*trainService.SeatHold{}
+	for _, email := range []string{"retried@example.com", "expired@example.com"} {
+		hold, err := server.HoldSeat(ctx, testTicket(email, "A"))
+		if err != nil {
+			t.Fatalf("HoldSeat failed: %v", err)
+		}
+		if hold, err = server.recordChargeKey(hold); err != nil {
+			t.Fatalf("recordChargeKey failed: %v", err)
+		}
+		if _, err := payments.Charge(ctx, hold.ChargeKey, email, hold.Ticket.Price); err != nil {
+			t.Fatalf("Charge failed: %v", err)
+		}
+		holds[email] = hold
+	}
+	server.settleInterruptedCharges(ctx)
+
+	// Confirming again reuses the charge key instead of charging twice.
+	ticket, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: holds["retried@example.com"].Token})
+	if err != nil {
+		t.Fatalf("ConfirmBooking failed: %v", err)
+	}
+	if len(payments.payments) != 2 {
+		t.Errorf("Expected no charge besides the two taken, got %d payments", len(payments.payments))
+	}
+
+	// The reaper keeps the other hold until its charge can be looked up.
+	now = now.Add(defaultHoldTTL)
+	if released, err := server.releaseExpiredHolds(); err != nil || released != 0 {
+		t.Fatalf("Expected the charged hold kept, got %d released and %v", released, err)
+	}
+	if _, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: holds["expired@example.com"].Token}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition confirming an expired hold, got %v", err)
+	}
+	payments.down = false
+	server.settleInterruptedCharges(ctx)
+	expired, err := server.firstUserTicket("expired@example.com")
+	if err != nil || expired.PaymentReference == "" || expired.PaymentReference == ticket.PaymentReference {
+		t.Errorf("Expected the expired hold booked with its own payment, got %v (err %v)", expired, err)
+	}
+}
+
+func TestSettleInterruptedGroupCharges(t *testing.T) {
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: newTestStore(testLayout, nil), payments: payments, duplicates: duplicateReject}
+	ctx := context.Background()
+
+	// The server stops after charging the group in section A and before
+	// charging the one in section B.
+	var paid, unpaid []*trainService.SeatHold
+	var payment string
+	for _, section := range []string{"A", "B"} {
+		tickets, err := server.validateGroup(testGroup(section, 2))
+		if err != nil {
+			t.Fatalf("validateGroup failed: %v", err)
+		}
+		holds, err := server.holdGroup(tickets)
+		if err != nil {
+			t.Fatalf("holdGroup failed: %v", err)
+		}
+		if section == "B" {
+			unpaid = holds
+			continue
+		}
+		paid = holds
+		if payment, err = payments.Charge(ctx, holds[0].ChargeKey, "family@example.com", totalPrice(tickets)); err != nil {
+			t.Fatalf("Charge failed: %v", err)
+		}
+	}
+
+	restarted := &TrainServer{store: server.store, payments: payments, duplicates: duplicateReject}
+	restarted.settleInterruptedCharges(ctx)
+	tickets := restarted.store.Tickets()
+	if len(tickets) != len(paid) {
+		t.Fatalf("Expected the paid group booked, got %v", tickets)
+	}
+	for i, ticket := range tickets {
+		if ticket.PaymentReference != payment || ticket.GroupReference == "" || !proto.Equal(ticket.Seat, paid[i].Ticket.Seat) {
+			t.Errorf("Expected %v booked in its held seat with payment %s, got %v", paid[i].Ticket, payment, ticket)
+		}
+	}
+	for _, hold := range unpaid {
+		if _, err := restarted.store.FindHold(hold.Token); err == nil {
+			t.Errorf("Expected the unpaid group's hold %s released", hold.Token)
+		}
+	}
+	if restarted.store.SeatCount("", "B") != 20 {
+		t.Errorf("Expected section B free again, got %d seats", restarted.store.SeatCount("", "B"))
+	}
+}
+
+func TestSettleInterruptedUpgradeCharges(t *testing.T) {
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts, payments: payments, duplicates: duplicateAllow}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	booked := map[string]*trainService.Ticket{}
+	upgrades := map[string]*trainService.PendingUpgrade{}
+	charged := map[string]string{}
+	for _, upgrade := range []struct{ email, class, to string }{
+		{email: "moved@example.com", to: "first"},
+		{email: "saver@example.com", class: "saver", to: "standard"},
+		{email: "unpaid@example.com", to: "first"},
+	} {
+		ticket := testJourney(upgrade.email, departure.Id, "S")
+		ticket.FareClass = upgrade.class
+		purchased, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		booked[upgrade.email] = purchased
+		pending, err := server.prepareUpgrade(&trainService.UpgradeTicketRequest{BookingReference: booked[upgrade.email].BookingReference, FareClass: upgrade.to})
+		if err != nil {
+			t.Fatalf("prepareUpgrade failed: %v", err)
+		}
+		upgrades[upgrade.email] = pending
+		if upgrade.email == "unpaid@example.com" {
+			continue
+		}
+		if charged[upgrade.email], err = payments.Charge(ctx, pending.ChargeKey, upgrade.email, pending.Difference); err != nil {
+			t.Fatalf("Charge failed: %v", err)
+		}
+	}
+
+	restarted := &TrainServer{store: server.store, layouts: testLayouts, payments: payments, duplicates: duplicateAllow}
+	restarted.settleInterruptedCharges(ctx)
+	moved, _ := restarted.store.FindTicket(booked["moved@example.com"].BookingReference)
+	if moved.Section != "F" || len(moved.UpgradePayments) != 1 || moved.UpgradePayments[0].Reference != charged["moved@example.com"] {
+		t.Errorf("Expected the paid upgrade to first class made, got %v", moved)
+	}
+	saver, _ := restarted.store.FindTicket(booked["saver@example.com"].BookingReference)
+	if saver.FareClass != "standard" || !proto.Equal(saver.Seat, booked["saver@example.com"].Seat) || len(saver.UpgradePayments) != 1 {
+		t.Errorf("Expected the paid upgrade in place made, got %v", saver)
+	}
+	if unpaid, _ := restarted.store.FindTicket(booked["unpaid@example.com"].BookingReference); !proto.Equal(unpaid, booked["unpaid@example.com"]) {
+		t.Errorf("Expected the unpaid upgrade not made, got %v", unpaid)
+	}
+	if len(restarted.store.PendingUpgrades()) != 0 || len(restarted.store.Holds()) != 0 || restarted.store.SeatCount(departure.Id, "F") != 1 {
+		t.Errorf("Expected every upgrade settled and the unpaid one's seat released, got %v and %v",
+			restarted.store.PendingUpgrades(), restarted.store.Holds())
+	}
+
+	// An upgrade made before the server stopped, but still on record, is
+	// only taken off record.
+	if err := restarted.store.AddPendingUpgrade(upgrades["saver@example.com"]); err != nil {
+		t.Fatalf("AddPendingUpgrade failed: %v", err)
+	}
+	restarted.settleInterruptedCharges(ctx)
+	if again, _ := restarted.store.FindTicket(saver.BookingReference); !proto.Equal(again, saver) || len(restarted.store.PendingUpgrades()) != 0 {
+		t.Errorf("Expected the upgrade left as it was, got %v", again)
+	}
+	if remaining := payments.remaining(charged["saver@example.com"]); !proto.Equal(remaining, upgrades["saver@example.com"].Difference) {
+		t.Errorf("Expected the upgrade payment kept, got %v left", remaining)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// normalisePromoCode makes promo codes case insensitive.
+func normalisePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// promoUses counts the booked and held tickets redeeming code, leaving out the
+// ticket with the booking reference excluded. Cancelled tickets and released
+// holds give their use back.
+func (s *TrainServer) promoUses(code, excluded string) int32 {
+	tickets := s.store.Tickets()
+	for _, hold := range s.store.Holds() {
+		tickets = append(tickets, hold.Ticket)
+	}
+	var uses int32
+	for _, ticket := range tickets {
+		if ticket.PromoCode == code && ticket.BookingReference != excluded {
+			uses++
+		}
+	}
+	return uses
+}
+
+// checkPromo returns the promo code called code if it can be redeemed today
+// on departure. Callers must hold s.mu.
+func (s *TrainServer) checkPromo(code string, departure *trainService.Departure, excluded string) (*trainService.PromoCode, error) {
+	promo, err := s.store.FindPromoCode(code)
+	if err != nil {
+		return nil, invalidField("promo_code", fmt.Sprintf("unknown promo code %s", code))
+	}
+	today := s.clock().UTC().Format(departureDateLayout)
+	if promo.ValidFrom != "" && today < promo.ValidFrom {
+		return nil, preconditionFailed("PROMO_NOT_STARTED", "promo_code",
+			fmt.Sprintf("promo code %s is valid from %s", code, promo.ValidFrom))
+	}
+	if promo.ValidUntil != "" && today > promo.ValidUntil {
+		return nil, preconditionFailed("PROMO_EXPIRED", "promo_code",
+			fmt.Sprintf("promo code %s expired on %s", code, promo.ValidUntil))
+	}
+	if len(promo.RouteIds) > 0 && !containsString(promo.RouteIds, departure.RouteId) {
+		return nil, preconditionFailed("PROMO_NOT_VALID_ON_ROUTE", "promo_code",
+			fmt.Sprintf("promo code %s is only valid on routes %s", code, strings.Join(promo.RouteIds, ", ")))
+	}
+	if promo.MaxUses > 0 && s.promoUses(code, excluded) >= promo.MaxUses {
+		return nil, preconditionFailed("PROMO_USED_UP", "promo_code",
+			fmt.Sprintf("promo code %s has been used %d times, its limit", code, promo.MaxUses))
+	}
+	return promo, nil
+}
+
+// applyPromo takes promo off the price of quote, never below zero. Amounts
+// off in another currency are converted with rates.
+func applyPromo(quote *trainService.FareQuote, promo *trainService.PromoCode, rates *exchangeRates) {
+	price := quote.Price
+	var amount int64
+	var description string
+	if promo.PercentOff > 0 {
+		amount = int64(math.Round(float64(price.MinorUnits) * float64(promo.PercentOff) / 100))
+		description = fmt.Sprintf("promo code %s: %g%% off", promo.Code, promo.PercentOff)
+	} else {
+		amount = rates.convert(promo.AmountOff, price.CurrencyCode).MinorUnits
+		description = fmt.Sprintf("promo code %s: %s off", promo.Code, trainService.FormatMoney(promo.AmountOff))
+	}
+	amount = min(amount, price.MinorUnits)
+	quote.Price = &trainService.Money{CurrencyCode: price.CurrencyCode, MinorUnits: price.MinorUnits - amount}
+	quote.Discounts = append(quote.Discounts, &trainService.Discount{
+		Code:        promo.Code,
+		Description: description,
+		Amount:      &trainService.Money{CurrencyCode: price.CurrencyCode, MinorUnits: amount},
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *TrainServer) validatePromoCode(req *trainService.PromoCode) error {
+	if err := requireFields("code field is empty", field{"code", req.Code}); err != nil {
+		return err
+	}
+	switch {
+	case req.PercentOff < 0 || req.PercentOff > 100:
+		return invalidField("percent_off", "percent off must be between 0 and 100")
+	case req.AmountOff.GetMinorUnits() < 0:
+		return invalidField("amount_off", "amount off must not be negative")
+	case (req.PercentOff > 0) == (req.AmountOff.GetMinorUnits() > 0):
+		return invalidField("percent_off", "exactly one of percent off and amount off must be set")
+	case req.MaxUses < 0:
+		return invalidField("max_uses", "max uses must not be negative")
+	}
+	if req.AmountOff != nil {
+		if _, ok := s.exchangeRates().rate(req.AmountOff.CurrencyCode); !ok {
+			return invalidField("amount_off.currency_code", fmt.Sprintf("currency %s is not supported", req.AmountOff.CurrencyCode))
+		}
+	}
+	for _, date := range []field{{"valid_from", req.ValidFrom}, {"valid_until", req.ValidUntil}} {
+		if _, err := time.Parse(departureDateLayout, date.value); date.value != "" && err != nil {
+			return invalidField(date.name, fmt.Sprintf("%q is not a valid date (YYYY-MM-DD)", date.value))
+		}
+	}
+	if req.ValidFrom != "" && req.ValidUntil != "" && req.ValidUntil < req.ValidFrom {
+		return invalidField("valid_until", "valid until is before valid from")
+	}
+	for _, route := range req.RouteIds {
+		if _, err := s.store.FindRoute(route); err != nil {
+			return invalidField("route_ids", fmt.Sprintf("unknown route %s", route))
+		}
+	}
+	return nil
+}
+
+func (s *TrainServer) CreatePromoCode(ctx context.Context, req *trainService.PromoCode) (*trainService.PromoCode, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+
+	promo := proto.Clone(req).(*trainService.PromoCode)
+	promo.Code = normalisePromoCode(promo.Code)
+	promo.Uses = 0
+	if promo.AmountOff != nil {
+		promo.AmountOff.CurrencyCode = strings.ToUpper(promo.AmountOff.CurrencyCode)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.validatePromoCode(promo); err != nil {
+		return nil, err
+	}
+	err := s.store.AddPromoCode(promo)
+	if errors.Is(err, errPromoCodeExists) {
+		return nil, withDetails(codes.AlreadyExists, fmt.Sprintf("promo code %s already exists", promo.Code),
+			&errdetails.ResourceInfo{ResourceType: "promo code", ResourceName: promo.Code})
+	}
+	if err != nil {
+		return nil, internalError("failed to save promo code", err)
+	}
+	return promo, nil
+}
+
+func (s *TrainServer) ListPromoCodes(req *trainService.ListPromoCodesRequest, stream trainService.TrainService_ListPromoCodesServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+
+	s.mu.RLock()
+	var promos []*trainService.PromoCode
+	for _, promo := range s.store.PromoCodes() {
+		promo = proto.Clone(promo).(*trainService.PromoCode)
+		promo.Uses = s.promoUses(promo.Code, "")
+		promos = append(promos, promo)
+	}
+	s.mu.RUnlock()
+
+	for _, promo := range promos {
+		if err := stream.Send(promo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type promoStream struct {
+	grpc.ServerStream
+	data []*trainService.PromoCode
+}
+
+func (s *promoStream) Send(promo *trainService.PromoCode) error {
+	s.data = append(s.data, promo)
+	return nil
+}
+
+func TestCreatePromoCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      *trainService.PromoCode
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Percent off",
+			request:      &trainService.PromoCode{Code: "spring20", PercentOff: 20, ValidFrom: "2024-01-01", ValidUntil: "2024-03-31", MaxUses: 1},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Amount off on a route",
+			request:      &trainService.PromoCode{Code: "FIVE", AmountOff: eur(500), RouteIds: []string{"LON-PAR"}},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "Duplicate code",
+			request:      &trainService.PromoCode{Code: "Spring20", PercentOff: 10},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "Nil request",
+			request:      nil,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Missing code",
+			request:      &trainService.PromoCode{PercentOff: 10},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Percent and amount off",
+			request:      &trainService.PromoCode{Code: "BOTH", PercentOff: 10, AmountOff: eur(500)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "No discount",
+			request:      &trainService.PromoCode{Code: "NOTHING"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "More than 100 percent off",
+			request:      &trainService.PromoCode{Code: "FREE", PercentOff: 150},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid date",
+			request:      &trainService.PromoCode{Code: "DATE", PercentOff: 10, ValidUntil: "31/03/2024"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Window ending before it starts",
+			request:      &trainService.PromoCode{Code: "WINDOW", PercentOff: 10, ValidFrom: "2024-03-31", ValidUntil: "2024-01-01"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Unknown route",
+			request:      &trainService.PromoCode{Code: "BRU", PercentOff: 10, RouteIds: []string{"LON-BRU"}},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	server := &TrainServer{store: newRouteStore()}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := server.CreatePromoCode(context.Background(), tc.request)
+			if status.Code(err) != tc.expectedCode {
+				t.Errorf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+		})
+	}
+
+	stream := &promoStream{}
+	if err := server.ListPromoCodes(&trainService.ListPromoCodesRequest{}, stream); err != nil {
+		t.Fatalf("ListPromoCodes failed: %v", err)
+	}
+	if len(stream.data) != 2 || stream.data[0].Code != "SPRING20" || stream.data[1].Code != "FIVE" {
+		t.Errorf("Expected promo codes SPRING20 and FIVE, got %v", stream.data)
+	}
+}
+
+func TestPromoCodeRedemption(t *testing.T) {
+	store := newRouteStore()
+	server := &TrainServer{store: store, duplicates: duplicateAllow,
+		now: func() time.Time { return time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC) }}
+	ctx := context.Background()
+
+	// Adults pay 79.25 from LON to PAR in section B.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	// The departure with the empty ID has no route.
+	store.AddDeparture(testDeparture("", testLayout))
+	for _, promo := range []*trainService.PromoCode{
+		{Code: "SPRING20", PercentOff: 20, ValidFrom: "2024-01-01", ValidUntil: "2024-03-31", MaxUses: 1},
+		{Code: "FIVE", AmountOff: eur(500), RouteIds: []string{"LON-PAR"}},
+		{Code: "SUMMER", PercentOff: 10, ValidFrom: "2024-06-01"},
+		{Code: "WINTER", PercentOff: 10, ValidUntil: "2024-01-31"},
+	} {
+		if _, err := server.CreatePromoCode(ctx, promo); err != nil {
+			t.Fatalf("CreatePromoCode failed: %v", err)
+		}
+	}
+	purchase := func(email, code string) (*trainService.Ticket, error) {
+		ticket := testJourney(email, departure.Id, "B")
+		ticket.PromoCode = code
+		return server.PurchaseTicket(ctx, ticket)
+	}
+	uses := func(code string) int32 {
+		t.Helper()
+		stream := &promoStream{}
+		if err := server.ListPromoCodes(&trainService.ListPromoCodesRequest{}, stream); err != nil {
+			t.Fatalf("ListPromoCodes failed: %v", err)
+		}
+		for _, promo := range stream.data {
+			if promo.Code == code {
+				return promo.Uses
+			}
+		}
+		t.Fatalf("Promo code %s not listed", code)
+		return 0
+	}
+
+	first, err := purchase("first@example.com", "spring20")
+	if err != nil {
+		t.Fatalf("PurchaseTicket with SPRING20 failed: %v", err)
+	}
+	if !proto.Equal(first.Price, eur(6340)) || !proto.Equal(first.OriginalFare, eur(7925)) || len(first.Discounts) != 1 ||
+		first.Discounts[0].Code != "SPRING20" || !proto.Equal(first.Discounts[0].Amount, eur(1585)) {
+		t.Errorf("Expected 20%% off 79.25 on the receipt, got %v", first)
+	}
+	if _, err := purchase("second@example.com", "SPRING20"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition once SPRING20 is used up, got %v", err)
+	}
+	if uses("SPRING20") != 1 {
+		t.Errorf("Expected SPRING20 used once, got %d", uses("SPRING20"))
+	}
+	// A cancelled booking gives its use back.
+	if _, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: first.BookingReference}); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if _, err := purchase("second@example.com", "SPRING20"); err != nil {
+		t.Errorf("PurchaseTicket with SPRING20 after cancellation failed: %v", err)
+	}
+
+	for code, expected := range map[string]codes.Code{
+		"SUMMER":  codes.FailedPrecondition,
+		"WINTER":  codes.FailedPrecondition,
+		"UNKNOWN": codes.InvalidArgument,
+	} {
+		if _, err := purchase("third@example.com", code); status.Code(err) != expected {
+			t.Errorf("Expected %v for promo code %s, got %v", expected, code, err)
+		}
+	}
+
+	// FIVE is only valid on route LON-PAR.
+	ticket := testTicket("third@example.com", "A")
+	ticket.From, ticket.To, ticket.PromoCode = "London", "Paris", "FIVE"
+	if _, err := server.PurchaseTicket(ctx, ticket); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for FIVE off its route, got %v", err)
+	}
+
+	// Quotes lock the discounted fare.
+	quote, err := server.QuoteFare(ctx, &trainService.Ticket{DepartureId: departure.Id, From: "LON", To: "PAR", Section: "B", PromoCode: "five"})
+	if err != nil {
+		t.Fatalf("QuoteFare failed: %v", err)
+	}
+	if !proto.Equal(quote.Price, eur(7425)) || !proto.Equal(quote.OriginalFare, eur(7925)) || len(quote.Discounts) != 1 {
+		t.Errorf("Expected 5 off 79.25, got %v", quote)
+	}
+	ticket = testJourney("fourth@example.com", departure.Id, "B")
+	ticket.QuoteToken = quote.QuoteToken
+	if _, err := server.PurchaseTicket(ctx, ticket); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a token quoted with another promo code, got %v", err)
+	}
+	ticket.PromoCode = "FIVE"
+	booked, err := server.PurchaseTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("PurchaseTicket with a quote token failed: %v", err)
+	}
+	if !proto.Equal(booked.Price, eur(7425)) || booked.PromoCode != "FIVE" || len(booked.Discounts) != 1 {
+		t.Errorf("Expected the locked discounted fare, got %v", booked)
+	}
+}
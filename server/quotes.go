@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+)
+
+// defaultQuoteTTL is how long a quoted fare is honoured when the server has no
+// other window configured.
+const defaultQuoteTTL = 5 * time.Minute
+
+// quoteClaims is the journey and price a quote token locks.
+type quoteClaims struct {
+	Departure    string                     `json:"departure"`
+	From         string                     `json:"from"`
+	To           string                     `json:"to"`
+	Section      string                     `json:"section"`
+	FareClass    string                     `json:"fare_class,omitempty"`
+	Passenger    trainService.PassengerType `json:"passenger"`
+	PromoCode    string                     `json:"promo_code,omitempty"`
+	OriginalFare *trainService.Money        `json:"original_fare"`
+	Discounts    []*trainService.Discount   `json:"discounts,omitempty"`
+	Price        *trainService.Money        `json:"price"`
+	Expires      int64                      `json:"expires"` // Unix seconds
+}
+
+func (c quoteClaims) matches(departure *trainService.Departure, req *trainService.Ticket) bool {
+	return c.Departure == departure.Id && c.From == req.From && c.To == req.To &&
+		c.Section == req.Section && c.FareClass == req.FareClass && c.Passenger == req.PassengerType && c.PromoCode == req.PromoCode &&
+		c.Price.GetCurrencyCode() == req.Currency
+}
+
+func (s *TrainServer) signQuote(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.quoteKey)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// lockQuote attaches a token to quote that lets req's journey be bought at the
+// quoted price until the token expires. The token is signed rather than
+// stored, so locks cost nothing until they are used.
+func (s *TrainServer) lockQuote(quote *trainService.FareQuote, departure *trainService.Departure, req *trainService.Ticket) {
+	ttl := s.quoteTTL
+	if ttl <= 0 {
+		ttl = defaultQuoteTTL
+	}
+	expires := s.clock().Add(ttl).Truncate(time.Second)
+	payload, _ := json.Marshal(quoteClaims{
+		Departure:    departure.Id,
+		From:         req.From,
+		To:           req.To,
+		Section:      req.Section,
+		FareClass:    quote.FareClass,
+		Passenger:    req.PassengerType,
+		PromoCode:    req.PromoCode,
+		OriginalFare: quote.OriginalFare,
+		Discounts:    quote.Discounts,
+		Price:        quote.Price,
+		Expires:      expires.Unix(),
+	})
+	quote.QuoteToken = base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(s.signQuote(payload))
+	quote.ExpiresAt = expires.UTC().Format(time.RFC3339)
+}
+
+// fare returns what req pays for travelled on departure in req.Currency, which
+// must be supported: the fare locked by its quote token, if any, or else the
+// current fare less its promo code.
+// Tickets with the booking reference replaced do not count against the promo
+// code's usage limit. Callers must hold s.mu.
+func (s *TrainServer) fare(departure *trainService.Departure, travelled leg, req *trainService.Ticket, replaced string) (*trainService.FareQuote, error) {
+	section, _ := findSection(departure, req.Section)
+	class, _, err := s.fareTable().fareClass(req.FareClass, section)
+	if err != nil {
+		return nil, err
+	}
+	req.FareClass = class
+	if req.QuoteToken != "" {
+		return s.lockedFare(departure, req, replaced)
+	}
+	quote, err := s.quote(departure, travelled, req.Section, req.FareClass, req.PassengerType, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if req.PromoCode != "" {
+		promo, err := s.checkPromo(req.PromoCode, departure, replaced)
+		if err != nil {
+			return nil, err
+		}
+		applyPromo(quote, promo, s.exchangeRates())
+	}
+	return quote, nil
+}
+
+// lockedFare returns the fare locked by req.QuoteToken for the journey req
+// books on departure. A locked promo code must still be redeemable.
+func (s *TrainServer) lockedFare(departure *trainService.Departure, req *trainService.Ticket, replaced string) (*trainService.FareQuote, error) {
+	encodedPayload, encodedMac, _ := strings.Cut(req.QuoteToken, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, invalidField("quote_token", "quote token is malformed")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil || !hmac.Equal(mac, s.signQuote(payload)) {
+		return nil, invalidField("quote_token", "quote token is not valid")
+	}
+	var claims quoteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, invalidField("quote_token", "quote token is malformed")
+	}
+	if !claims.matches(departure, req) {
+		return nil, invalidField("quote_token", "quote token was issued for a different journey")
+	}
+	if expires := time.Unix(claims.Expires, 0); s.clock().After(expires) {
+		return nil, preconditionFailed("QUOTE_EXPIRED", "quote_token",
+			fmt.Sprintf("quote expired at %s, request a new quote", expires.UTC().Format(time.RFC3339)))
+	}
+	if claims.PromoCode != "" {
+		if _, err := s.checkPromo(claims.PromoCode, departure, replaced); err != nil {
+			return nil, err
+		}
+	}
+	return &trainService.FareQuote{Price: claims.Price, OriginalFare: claims.OriginalFare, Discounts: claims.Discounts}, nil
+}
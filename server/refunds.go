@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/protobuf/proto"
+)
+
+// refundPolicy decides how much of the price of a cancelled ticket is paid
+// back. Tickets cancelled before every fee tier applies are refunded in full,
+// and tickets cancelled after departure are not refunded.
+type refundPolicy struct {
+	// Fee tiers keep a percentage of the price by the time left before
+	// departure. The tier with the smallest WithinHours not below the hours
+	// left applies.
+	Fees []feeTier `json:"fees"`
+	// NonRefundableSections are never refunded.
+	NonRefundableSections []string `json:"non_refundable_sections"`
+	// NonRefundable tickets are never refunded.
+	NonRefundable bool `json:"non_refundable"`
+}
+
+type feeTier struct {
+	WithinHours float64 `json:"within_hours"`
+	Percent     float64 `json:"percent"`
+}
+
+var defaultRefunds = refundPolicy{
+	Fees: []feeTier{
+		{WithinHours: 2, Percent: 50},
+		{WithinHours: 48, Percent: 10},
+	},
+}
+
+func (p *refundPolicy) validate() error {
+	for _, tier := range p.Fees {
+		if tier.WithinHours < 0 || tier.Percent < 0 || tier.Percent > 100 {
+			return fmt.Errorf("fee tier %+v needs positive hours and a percentage between 0 and 100", tier)
+		}
+	}
+	return nil
+}
+
+func (p *refundPolicy) sortFees() {
+	sort.Slice(p.Fees, func(i, j int) bool { return p.Fees[i].WithinHours < p.Fees[j].WithinHours })
+}
+
+// feePercent returns the share of the price kept when a ticket in a section of
+// departure is cancelled at now, and the rule that applied.
+func (p *refundPolicy) feePercent(departure *trainService.Departure, section string, now time.Time) (float64, string) {
+	if p.NonRefundable {
+		return 100, "the fare is non-refundable"
+	}
+	if containsString(p.NonRefundableSections, section) {
+		return 100, fmt.Sprintf("tickets in section %s are non-refundable", section)
+	}
+	at, ok := departsAt(departure)
+	if !ok {
+		return 0, "cancelled before departure"
+	}
+	hoursLeft := at.Sub(now).Hours()
+	if hoursLeft < 0 {
+		return 100, "cancelled after departure"
+	}
+	for _, tier := range p.Fees {
+		if hoursLeft <= tier.WithinHours {
+			return tier.Percent, fmt.Sprintf("cancelled within %v hours of departure, %v%% fee", tier.WithinHours, tier.Percent)
+		}
+	}
+	return 0, "cancelled before the cancellation fees apply"
+}
+
+// refundOf works out the refund of a ticket on departure cancelled at now. Fees
+// are kept from the amount paid.
+func (p *refundPolicy) refundOf(ticket *trainService.Ticket, departure *trainService.Departure, now time.Time) *trainService.Refund {
+	paid := ticket.AmountPaid
+	percent, reason := p.feePercent(departure, ticket.Section, now)
+	fee := int64(math.Round(float64(paid.MinorUnits) * percent / 100))
+	refund := &trainService.Refund{
+		Amount: &trainService.Money{CurrencyCode: paid.CurrencyCode, MinorUnits: paid.MinorUnits - fee},
+		Fee:    &trainService.Money{CurrencyCode: paid.CurrencyCode, MinorUnits: fee},
+		Reason: reason,
+	}
+	switch {
+	case refund.Amount.MinorUnits == 0 && fee > 0:
+		refund.Status = trainService.RefundStatus_NOT_REFUNDED
+	case fee > 0:
+		refund.Status = trainService.RefundStatus_PARTIALLY_REFUNDED
+	}
+	return refund
+}
+
+// cancelled returns a copy of ticket with the refund due on cancelling it now
+// filled in. Tickets that were not paid for are returned as they are. Callers
+// must hold s.mu.
+func (s *TrainServer) cancelled(ticket *trainService.Ticket) *trainService.Ticket {
+	if ticket.PaymentReference == "" || ticket.AmountPaid == nil {
+		return ticket
+	}
+	departure, err := s.store.FindDeparture(ticket.DepartureId)
+	if err != nil {
+		// Tickets cannot outlive their departure, so this is not expected;
+		// refund in full rather than keep a fee on a guess.
+		departure = &trainService.Departure{Id: ticket.DepartureId}
+	}
+	ticket = proto.Clone(ticket).(*trainService.Ticket)
+	ticket.Refund = s.fareTable().refundPolicy(ticket.FareClass).refundOf(ticket, departure, s.clock())
+	return ticket
+}
+
+// refund pays back the refund of a cancelled ticket through the payment
+// provider, marking it REFUND_FAILED if the provider does not. Upgrade charges
+// are paid back first, newest first, and the rest from the payment that
+// bought the ticket. Callers must not hold s.mu.
+func (s *TrainServer) refund(ctx context.Context, ticket *trainService.Ticket) {
+	if ticket.Refund == nil || ticket.Refund.Amount.MinorUnits == 0 {
+		return
+	}
+	payments := []*trainService.Payment{{Reference: ticket.PaymentReference, Amount: ticket.AmountPaid}}
+	payments = append(payments, ticket.UpgradePayments...)
+
+	left := ticket.Refund.Amount.MinorUnits
+	for i := len(payments) - 1; i >= 0 && left > 0; i-- {
+		// The original payment covers whatever the upgrades do not.
+		amount := left
+		if i > 0 {
+			amount = min(left, payments[i].Amount.MinorUnits)
+		}
+		left -= amount
+		refund := &trainService.Money{CurrencyCode: ticket.Refund.Amount.CurrencyCode, MinorUnits: amount}
+		// The ticket is already cancelled, so the refund is made even if the
+		// client goes away.
+		err := s.paymentProvider().Refund(context.WithoutCancel(ctx), payments[i].Reference, refund)
+		if err != nil {
+			log.Printf("failed to refund %s of payment %s for ticket %s: %v",
+				trainService.FormatMoney(refund), payments[i].Reference, ticket.BookingReference, err)
+			ticket.Refund.Status = trainService.RefundStatus_REFUND_FAILED
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRefundPolicy(t *testing.T) {
+	departs := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	scheduled := &trainService.Departure{Id: "IC101-20240304-0930", Date: "2024-03-04", DepartureTime: "09:30"}
+	policy := refundPolicy{
+		Fees:                  defaultRefunds.Fees,
+		NonRefundableSections: []string{"S"},
+	}
+
+	tests := []struct {
+		name           string
+		departure      *trainService.Departure
+		section        string
+		now            time.Time
+		expectedAmount int64
+		expectedFee    int64
+		expectedStatus trainService.RefundStatus
+	}{
+		{
+			name:           "Cancelled before the fees apply",
+			departure:      scheduled,
+			section:        "A",
+			now:            departs.Add(-72 * time.Hour),
+			expectedAmount: 7925,
+			expectedStatus: trainService.RefundStatus_REFUNDED,
+		},
+		{
+			name:           "Cancelled the day before",
+			departure:      scheduled,
+			section:        "A",
+			now:            departs.Add(-24 * time.Hour),
+			expectedAmount: 7132,
+			expectedFee:    793,
+			expectedStatus: trainService.RefundStatus_PARTIALLY_REFUNDED,
+		},
+		{
+			name:           "Cancelled just before departure",
+			departure:      scheduled,
+			section:        "A",
+			now:            departs.Add(-time.Hour),
+			expectedAmount: 3962,
+			expectedFee:    3963,
+			expectedStatus: trainService.RefundStatus_PARTIALLY_REFUNDED,
+		},
+		{
+			name:           "Cancelled after departure",
+			departure:      scheduled,
+			section:        "A",
+			now:            departs.Add(time.Minute),
+			expectedFee:    7925,
+			expectedStatus: trainService.RefundStatus_NOT_REFUNDED,
+		},
+		{
+			name:           "Non-refundable section",
+			departure:      scheduled,
+			section:        "S",
+			now:            departs.Add(-72 * time.Hour),
+			expectedFee:    7925,
+			expectedStatus: trainService.RefundStatus_NOT_REFUNDED,
+		},
+		{
+			name:           "Departure without a schedule",
+			departure:      &trainService.Departure{},
+			section:        "A",
+			now:            departs,
+			expectedAmount: 7925,
+			expectedStatus: trainService.RefundStatus_REFUNDED,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ticket := &trainService.Ticket{Section: tc.section, Price: eur(9000), AmountPaid: eur(7925)}
+			refund := policy.refundOf(ticket, tc.departure, tc.now)
+
+			if !proto.Equal(refund.Amount, eur(tc.expectedAmount)) || !proto.Equal(refund.Fee, eur(tc.expectedFee)) || refund.Status != tc.expectedStatus {
+				t.Errorf("Expected %d refunded with a fee of %d (%v), got %v", tc.expectedAmount, tc.expectedFee, tc.expectedStatus, refund)
+			}
+			if refund.Reason == "" {
+				t.Errorf("Expected the rule applied as reason, got %v", refund)
+			}
+		})
+	}
+}
+
+func TestCancellationRefunds(t *testing.T) {
+	now := time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: newRouteStore(), payments: payments, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	purchase := func(email string) *trainService.Ticket {
+		ticket := testJourney(email, departure.Id, "B")
+		booked, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		return booked
+	}
+
+	// Cancelled 21.5 hours before departure, within the 10% fee tier.
+	booked := purchase("deepak@example.com")
+	cancelled, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: booked.BookingReference})
+	if err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	fee := int64(float64(booked.AmountPaid.MinorUnits)/10 + 0.5)
+	if refund := cancelled.Refund; refund.Status != trainService.RefundStatus_PARTIALLY_REFUNDED ||
+		!proto.Equal(refund.Fee, eur(fee)) || !proto.Equal(refund.Amount, eur(booked.AmountPaid.MinorUnits-fee)) {
+		t.Errorf("Expected a 10%% fee on %v, got %v", booked.AmountPaid, refund)
+	}
+	if !proto.Equal(payments.remaining(booked.PaymentReference), eur(fee)) {
+		t.Errorf("Expected the provider to keep only the fee, got %v", payments.remaining(booked.PaymentReference))
+	}
+
+	// A ticket replaced by a new purchase is refunded like a cancellation.
+	server.duplicates = duplicateReplace
+	replaced := purchase("deepak@example.com")
+	purchase("deepak@example.com")
+	fee = int64(float64(replaced.AmountPaid.MinorUnits)/10 + 0.5)
+	if !proto.Equal(payments.remaining(replaced.PaymentReference), eur(fee)) {
+		t.Errorf("Expected the replaced ticket refunded less the fee, got %v", payments.remaining(replaced.PaymentReference))
+	}
+	server.duplicates = duplicateAllow
+
+	// A refund the provider turns down is reported on the cancelled ticket.
+	booked = purchase("test@example.com")
+	delete(payments.payments, booked.PaymentReference)
+	cancelled, err = server.CancelTicket(ctx, &trainService.User{Email: "test@example.com"})
+	if err != nil {
+		t.Fatalf("CancelTicket failed: %v", err)
+	}
+	if cancelled.Refund.Status != trainService.RefundStatus_REFUND_FAILED {
+		t.Errorf("Expected REFUND_FAILED, got %v", cancelled.Refund)
+	}
+	if _, err := server.store.FindTicket(booked.BookingReference); err == nil {
+		t.Errorf("Expected the ticket cancelled anyway")
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+var errInvalidLeg = errors.New("stations are not stops of the departure in travel order")
+
+// leg is the part of a departure a ticket travels on, as the route segments
+// first up to but not including last. Segment i runs from stop i to stop i+1.
+type leg struct {
+	first, last int
+}
+
+func (l leg) overlaps(other leg) bool {
+	return l.first < other.last && other.first < l.last
+}
+
+// freeSeats returns the fewest free seats on any segment of l, given the free
+// seats per segment.
+func (l leg) freeSeats(free []int) int {
+	seats := free[l.first]
+	for _, count := range free[l.first+1 : l.last] {
+		seats = min(seats, count)
+	}
+	return seats
+}
+
+// segmentCount returns the number of segments of departure. Departures without
+// stops, such as those created before routes existed, are sold as a single
+// segment.
+func segmentCount(departure *trainService.Departure) int {
+	if len(departure.Stops) < 2 {
+		return 1
+	}
+	return len(departure.Stops) - 1
+}
+
+// legOf returns the leg travelled between the stations from and to. Any
+// stations are accepted on a departure without stops.
+func legOf(departure *trainService.Departure, from, to string) (leg, error) {
+	if len(departure.Stops) < 2 {
+		return leg{0, 1}, nil
+	}
+	first, last := -1, -1
+	for i, stop := range departure.Stops {
+		switch stop {
+		case from:
+			first = i
+		case to:
+			last = i
+		}
+	}
+	if first < 0 || last < 0 || first >= last {
+		return leg{}, errInvalidLeg
+	}
+	return leg{first, last}, nil
+}
+
+// ticketLeg returns the leg of a stored ticket, or the whole departure if its
+// stations no longer match.
+func ticketLeg(departure *trainService.Departure, ticket *trainService.Ticket) leg {
+	travelled, err := legOf(departure, ticket.From, ticket.To)
+	if err != nil {
+		return leg{0, segmentCount(departure)}
+	}
+	return travelled
+}
+
+// validateLeg checks that a ticket's stations are stops of departure in travel
+// order and returns the leg between them.
+func validateLeg(departure *trainService.Departure, from, to string) (leg, error) {
+	travelled, err := legOf(departure, from, to)
+	if err == nil {
+		return travelled, nil
+	}
+	stops := map[string]bool{}
+	for _, stop := range departure.Stops {
+		stops[stop] = true
+	}
+	switch {
+	case !stops[from]:
+		return leg{}, invalidField("from", fmt.Sprintf("departure %s does not stop at %s", departure.Id, from))
+	case !stops[to]:
+		return leg{}, invalidField("to", fmt.Sprintf("departure %s does not stop at %s", departure.Id, to))
+	}
+	return leg{}, invalidField("to", fmt.Sprintf("departure %s does not reach %s after %s", departure.Id, to, from))
+}
+
+// legSeats returns the seats free on every segment of travelled in a section
+// of departure. The seat of released, if any, counts as free so a ticket can
+// be replaced or moved within its own section. Callers must hold s.mu.
+func (s *TrainServer) legSeats(departure *trainService.Departure, section string, travelled leg, released *trainService.Ticket) int {
+	free := s.store.SegmentSeatCounts(departure.Id, section)
+	if len(free) < travelled.last {
+		return 0
+	}
+	if released != nil && released.Section == section {
+		releasedLeg := ticketLeg(departure, released)
+		for i := releasedLeg.first; i < releasedLeg.last && i < len(free); i++ {
+			free[i]++
+		}
+	}
+	return travelled.freeSeats(free)
+}
+
+func (s *TrainServer) AddStation(ctx context.Context, req *trainService.Station) (*trainService.Station, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("(Code, Name) fields are empty", field{"code", req.Code}, field{"name", req.Name}); err != nil {
+		return nil, err
+	}
+
+	station := &trainService.Station{Code: req.Code, Name: req.Name}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.store.AddStation(station)
+	if errors.Is(err, errStationExists) {
+		return nil, withDetails(codes.AlreadyExists, fmt.Sprintf("station %s already exists", station.Code),
+			&errdetails.ResourceInfo{ResourceType: "station", ResourceName: station.Code})
+	}
+	if err != nil {
+		return nil, internalError("failed to save station", err)
+	}
+	return station, nil
+}
+
+func (s *TrainServer) ListStations(req *trainService.ListStationsRequest, stream trainService.TrainService_ListStationsServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+
+	s.mu.RLock()
+	stations := s.store.Stations()
+	s.mu.RUnlock()
+
+	for _, station := range stations {
+		if err := stream.Send(station); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TrainServer) CreateRoute(ctx context.Context, req *trainService.Route) (*trainService.Route, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("id field is empty", field{"id", req.Id}); err != nil {
+		return nil, err
+	}
+	if len(req.Stops) < 2 {
+		return nil, invalidField("stops", "route needs at least two stops")
+	}
+	if len(req.DistancesKm) != len(req.Stops)-1 {
+		return nil, invalidField("distances_km", fmt.Sprintf("route with %d stops needs %d distances", len(req.Stops), len(req.Stops)-1))
+	}
+	for i, distance := range req.DistancesKm {
+		if distance <= 0 {
+			return nil, invalidField(fmt.Sprintf("distances_km[%d]", i), "distance must be positive")
+		}
+	}
+
+	route := &trainService.Route{
+		Id:          req.Id,
+		Stops:       append([]string(nil), req.Stops...),
+		DistancesKm: append([]int32(nil), req.DistancesKm...),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	known := map[string]bool{}
+	for _, station := range s.store.Stations() {
+		known[station.Code] = true
+	}
+	seen := map[string]bool{}
+	for i, stop := range route.Stops {
+		name := fmt.Sprintf("stops[%d]", i)
+		if !known[stop] {
+			return nil, invalidField(name, fmt.Sprintf("station %s does not exist", stop))
+		}
+		if seen[stop] {
+			return nil, invalidField(name, fmt.Sprintf("station %s is listed twice", stop))
+		}
+		seen[stop] = true
+	}
+
+	err := s.store.AddRoute(route)
+	if errors.Is(err, errRouteExists) {
+		return nil, withDetails(codes.AlreadyExists, fmt.Sprintf("route %s already exists", route.Id),
+			&errdetails.ResourceInfo{ResourceType: "route", ResourceName: route.Id})
+	}
+	if err != nil {
+		return nil, internalError("failed to save route", err)
+	}
+	return route, nil
+}
+
+func (s *TrainServer) ListRoutes(req *trainService.ListRoutesRequest, stream trainService.TrainService_ListRoutesServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+
+	s.mu.RLock()
+	var routes []*trainService.Route
+	for _, route := range s.store.Routes() {
+		if req.Station == "" || servesStation(route, req.Station) {
+			routes = append(routes, route)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, route := range routes {
+		if err := stream.Send(route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func servesStation(route *trainService.Route, station string) bool {
+	for _, stop := range route.Stops {
+		if stop == station {
+			return true
+		}
+	}
+	return false
+}
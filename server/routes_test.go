@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStationsAndRoutes(t *testing.T) {
+	server := &TrainServer{store: newRouteStore()}
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		call         func() error
+		expectedCode codes.Code
+	}{
+		{
+			name: "Add station",
+			call: func() error {
+				_, err := server.AddStation(ctx, &trainService.Station{Code: "BRU", Name: "Brussels"})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "Duplicate station",
+			call: func() error {
+				_, err := server.AddStation(ctx, &trainService.Station{Code: "LON", Name: "London"})
+				return err
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "Station without name",
+			call: func() error {
+				_, err := server.AddStation(ctx, &trainService.Station{Code: "AMS"})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Create route",
+			call: func() error {
+				_, err := server.CreateRoute(ctx, &trainService.Route{Id: "LON-BRU", Stops: []string{"LON", "LIL", "BRU"}, DistancesKm: []int32{270, 110}})
+				return err
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "Duplicate route",
+			call: func() error {
+				_, err := server.CreateRoute(ctx, &trainService.Route{Id: "LON-PAR", Stops: []string{"LON", "PAR"}, DistancesKm: []int32{495}})
+				return err
+			},
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name: "Route with one stop",
+			call: func() error {
+				_, err := server.CreateRoute(ctx, &trainService.Route{Id: "LON", Stops: []string{"LON"}})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Route with missing distances",
+			call: func() error {
+				_, err := server.CreateRoute(ctx, &trainService.Route{Id: "LIL-BRU", Stops: []string{"LIL", "BRU"}})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Route with unknown station",
+			call: func() error {
+				_, err := server.CreateRoute(ctx, &trainService.Route{Id: "LON-AMS", Stops: []string{"LON", "AMS"}, DistancesKm: []int32{540}})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Route calling twice at a station",
+			call: func() error {
+				_, err := server.CreateRoute(ctx, &trainService.Route{Id: "LOOP", Stops: []string{"LON", "LIL", "LON"}, DistancesKm: []int32{270, 270}})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); status.Code(err) != tc.expectedCode {
+				t.Errorf("Expected code %v, got %v", tc.expectedCode, err)
+			}
+		})
+	}
+
+	if len(server.store.Stations()) != 4 {
+		t.Errorf("Expected 4 stations, got %d", len(server.store.Stations()))
+	}
+	routes := &routeStream{}
+	if err := server.ListRoutes(&trainService.ListRoutesRequest{Station: "BRU"}, routes); err != nil {
+		t.Fatalf("ListRoutes failed: %v", err)
+	}
+	if len(routes.data) != 1 || routes.data[0].Id != "LON-BRU" {
+		t.Errorf("Expected route LON-BRU to serve BRU, got %v", routes.data)
+	}
+}
+
+func TestSegmentBooking(t *testing.T) {
+	store := newRouteStore()
+	server := &TrainServer{store: store, duplicates: duplicateAllow}
+	ctx := context.Background()
+
+	schedule := testSchedule("IC101", "2024-03-01", "09:30")
+	schedule.Sections = []*trainService.SectionCapacity{{Section: "A", Rows: 1, SeatsPerRow: 1}}
+	departure := createDeparture(t, server, schedule)
+
+	purchase := func(email, from, to string) (*trainService.Ticket, error) {
+		ticket := testJourney(email, departure.Id, "A")
+		ticket.From, ticket.To = from, to
+		return server.PurchaseTicket(ctx, ticket)
+	}
+
+	// The only seat can be sold once per leg as long as the legs do not
+	// overlap.
+	first, err := purchase("first@example.com", "LON", "LIL")
+	if err != nil {
+		t.Fatalf("PurchaseTicket LON-LIL failed: %v", err)
+	}
+	second, err := purchase("second@example.com", "LIL", "PAR")
+	if err != nil {
+		t.Fatalf("PurchaseTicket LIL-PAR failed: %v", err)
+	}
+	if keyOf(first.Seat) != keyOf(second.Seat) {
+		t.Errorf("Expected both legs on the same seat, got %v and %v", first.Seat, second.Seat)
+	}
+	for _, leg := range [][2]string{{"LON", "PAR"}, {"LON", "LIL"}, {"LIL", "PAR"}} {
+		if _, err := purchase("third@example.com", leg[0], leg[1]); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("Expected %s-%s to be sold out, got %v", leg[0], leg[1], err)
+		}
+	}
+	if counts := store.SegmentSeatCounts(departure.Id, "A"); counts[0] != 0 || counts[1] != 0 {
+		t.Errorf("Expected no free seats on either segment, got %v", counts)
+	}
+
+	if _, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: first.BookingReference}); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if counts := store.SegmentSeatCounts(departure.Id, "A"); counts[0] != 1 || counts[1] != 0 {
+		t.Errorf("Expected the seat back on LON-LIL only, got %v", counts)
+	}
+	if _, err := purchase("third@example.com", "LON", "PAR"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected LON-PAR to stay sold out, got %v", err)
+	}
+	if _, err := purchase("third@example.com", "LON", "LIL"); err != nil {
+		t.Errorf("PurchaseTicket LON-LIL after cancellation failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		from, to, field string
+	}{
+		{"BRU", "PAR", "from"},
+		{"LON", "BRU", "to"},
+		{"PAR", "LON", "to"},
+		{"LIL", "LIL", "to"},
+	} {
+		_, err := purchase("fourth@example.com", tc.from, tc.to)
+		st := status.Convert(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %s-%s, got %v", tc.from, tc.to, err)
+			continue
+		}
+		for _, detail := range st.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok && badRequest.FieldViolations[0].Field != tc.field {
+				t.Errorf("Expected a violation on %s for %s-%s, got %v", tc.field, tc.from, tc.to, badRequest.FieldViolations)
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// seatLayout is the seat map of a section: rows are numbered from 1 to Rows
+// and seats within a row from 1 to SeatsPerRow.
+type seatLayout struct {
+	Rows        int32
+	SeatsPerRow int32
+	// Accessible seats are kept for passengers who need them.
+	Accessible map[seatKey]bool
+}
+
+func (l seatLayout) capacity() int {
+	return int(l.Rows * l.SeatsPerRow)
+}
+
+func (l seatLayout) contains(seat *trainService.Seat) bool {
+	return seat.Row >= 1 && seat.Row <= l.Rows && seat.Number >= 1 && seat.Number <= l.SeatsPerRow
+}
+
+type seatKey struct {
+	row, number int32
+}
+
+func keyOf(seat *trainService.Seat) seatKey {
+	return seatKey{seat.Row, seat.Number}
+}
+
+func seatLabel(seat *trainService.Seat) string {
+	return fmt.Sprintf("row %d seat %d", seat.Row, seat.Number)
+}
+
+// findSection returns the section of departure with the given name.
+func findSection(departure *trainService.Departure, section string) (*trainService.SectionCapacity, bool) {
+	for _, capacity := range departure.Sections {
+		if capacity.Section == section {
+			return capacity, true
+		}
+	}
+	return nil, false
+}
+
+// sectionLayout returns the seat map of a section of departure.
+func sectionLayout(departure *trainService.Departure, section string) (seatLayout, bool) {
+	capacity, ok := findSection(departure, section)
+	if !ok {
+		return seatLayout{}, false
+	}
+	layout := seatLayout{Rows: capacity.Rows, SeatsPerRow: capacity.SeatsPerRow, Accessible: map[seatKey]bool{}}
+	for _, seat := range capacity.AccessibleSeats {
+		layout.Accessible[keyOf(seat)] = true
+	}
+	return layout, true
+}
+
+// takenSeats returns the seats in a section of departure taken by tickets and
+// seat holds on legs overlapping travelled, except the seat of the ticket with
+// the given booking reference, if any. Callers must hold s.mu.
+func (s *TrainServer) takenSeats(departure *trainService.Departure, section string, travelled leg, reference string) map[seatKey]bool {
+	return takenBy(departure, s.seatedTickets(departure.Id), section, travelled, reference)
+}
+
+// takenBy returns the seats in a section of departure taken by tickets on legs
+// overlapping travelled, except the seat of the ticket with the given booking
+// reference, if any.
+func takenBy(departure *trainService.Departure, tickets []*trainService.Ticket, section string, travelled leg, reference string) map[seatKey]bool {
+	taken := map[seatKey]bool{}
+	for _, ticket := range tickets {
+		if ticket.Section != section || ticket.Seat == nil || (reference != "" && ticket.BookingReference == reference) {
+			continue
+		}
+		if ticketLeg(departure, ticket).overlaps(travelled) {
+			taken[keyOf(ticket.Seat)] = true
+		}
+	}
+	return taken
+}
+
+// firstFreeSeat returns the first seat of layout in row order that is not
+// taken and is accessible or not as asked, or nil if there is none.
+func firstFreeSeat(layout seatLayout, taken map[seatKey]bool, accessible bool) *trainService.Seat {
+	for row := int32(1); row <= layout.Rows; row++ {
+		for number := int32(1); number <= layout.SeatsPerRow; number++ {
+			key := seatKey{row, number}
+			if !taken[key] && layout.Accessible[key] == accessible {
+				return &trainService.Seat{Row: row, Number: number}
+			}
+		}
+	}
+	return nil
+}
+
+// noAccessibleSeat reports that a passenger who needs an accessible seat
+// cannot get one in a section of layout: a sold-out error when the section's
+// accessible seats are all taken, so the passenger can join the waitlist.
+func noAccessibleSeat(section string, layout seatLayout) error {
+	if len(layout.Accessible) == 0 {
+		return preconditionFailed("NO_ACCESSIBLE_SEATS", "section:"+section,
+			fmt.Sprintf("section %s has no accessible seats", section))
+	}
+	return withDetails(codes.ResourceExhausted, fmt.Sprintf("no accessible seats available in section %s", section), &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{
+			{Subject: "section:" + section, Description: "no accessible seats available"},
+		},
+	})
+}
+
+// allocateSeat picks a seat in a section of departure for the leg travelled,
+// either the requested one or the first free seat in row order. A passenger
+// who needs an accessible seat only gets one of the section's accessible
+// seats, and other passengers only get one when no other seat is free. Seats
+// are only taken by tickets and seat holds on overlapping legs, and the seat
+// of the ticket with the given booking reference, if any, counts as free so
+// passengers can move within their own section. Callers must hold s.mu.
+func (s *TrainServer) allocateSeat(departure *trainService.Departure, section string, travelled leg, requested *trainService.Seat, accessible bool, reference string) (*trainService.Seat, error) {
+	layout, ok := sectionLayout(departure, section)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "section %s has no seat map", section)
+	}
+
+	taken := s.takenSeats(departure, section, travelled, reference)
+	if requested != nil {
+		if !layout.contains(requested) {
+			return nil, invalidField("seat", fmt.Sprintf("%s does not exist in section %s", seatLabel(requested), section))
+		}
+		if taken[keyOf(requested)] {
+			return nil, preconditionFailed("SEAT_TAKEN", fmt.Sprintf("section:%s/row:%d/seat:%d", section, requested.Row, requested.Number),
+				fmt.Sprintf("%s in section %s is already taken", seatLabel(requested), section))
+		}
+		return &trainService.Seat{Row: requested.Row, Number: requested.Number}, nil
+	}
+
+	if seat := firstFreeSeat(layout, taken, accessible); seat != nil {
+		return seat, nil
+	}
+	if accessible {
+		return nil, noAccessibleSeat(section, layout)
+	}
+	if seat := firstFreeSeat(layout, taken, true); seat != nil {
+		return seat, nil
+	}
+	return nil, sectionSoldOut(section)
+}
+
+// allocateGroupSeats picks a seat in a section of departure for the leg
+// travelled for each passenger of a group, given whether they need an
+// accessible seat. Those who do get the first free accessible seats. The rest
+// of the group is kept together: their seats come from the fewest consecutive
+// rows with room for everyone, the earliest such rows first, and are taken in
+// row order, leaving accessible seats free when the group fits without them.
+// Callers must hold s.mu.
+func (s *TrainServer) allocateGroupSeats(departure *trainService.Departure, section string, travelled leg, accessible []bool) ([]*trainService.Seat, error) {
+	layout, ok := sectionLayout(departure, section)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "section %s has no seat map", section)
+	}
+
+	taken := s.takenSeats(departure, section, travelled, "")
+	seats := make([]*trainService.Seat, len(accessible))
+	together := 0
+	for i, needed := range accessible {
+		if !needed {
+			together++
+			continue
+		}
+		seat := firstFreeSeat(layout, taken, true)
+		if seat == nil {
+			return nil, noAccessibleSeat(section, layout)
+		}
+		taken[keyOf(seat)] = true
+		seats[i] = seat
+	}
+
+	block := seatBlock(layout, taken, together, false)
+	if block == nil {
+		block = seatBlock(layout, taken, together, true)
+	}
+	if block == nil {
+		return nil, sectionSoldOut(section)
+	}
+	for i := range seats {
+		if seats[i] == nil {
+			seats[i], block = block[0], block[1:]
+		}
+	}
+	return seats, nil
+}
+
+// seatBlock returns count free seats of layout from the fewest consecutive
+// rows that have them, the earliest such rows first, in row order. Accessible
+// seats are only included if asked. It returns nil if there are not enough
+// free seats.
+func seatBlock(layout seatLayout, taken map[seatKey]bool, count int, accessible bool) []*trainService.Seat {
+	free := make([][]*trainService.Seat, layout.Rows)
+	for row := int32(1); row <= layout.Rows; row++ {
+		for number := int32(1); number <= layout.SeatsPerRow; number++ {
+			key := seatKey{row, number}
+			if !taken[key] && (accessible || !layout.Accessible[key]) {
+				free[row-1] = append(free[row-1], &trainService.Seat{Row: row, Number: number})
+			}
+		}
+	}
+
+	for span := 1; span <= len(free); span++ {
+		for first := 0; first+span <= len(free); first++ {
+			var seats []*trainService.Seat
+			for _, row := range free[first : first+span] {
+				seats = append(seats, row...)
+			}
+			if len(seats) >= count {
+				return seats[:count]
+			}
+		}
+	}
+	return nil
+}
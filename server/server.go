@@ -2,30 +2,164 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
 )
 
+// TrainServer serves bookings for scheduled departures out of a BookingStore.
+// gRPC serves every RPC on its own goroutine, so the store is only accessed
+// with mu held.
+// Stored tickets are never modified in place; updates replace the ticket so
+// responses already handed to gRPC stay untouched.
 type TrainServer struct {
 	*trainService.UnimplementedTrainServiceServer
-	tickets   []*trainService.Ticket
-	seatCount map[string]int
+	mu         sync.RWMutex
+	store      BookingStore
+	consentKey []byte // signs seat swap consent tokens
+	duplicates duplicatePolicy
+	fares      *fareTable       // pricing rules, defaultFares if nil
+	rates      *exchangeRates   // currency conversions, defaultRates if nil
+	quoteKey   []byte           // signs fare quote tokens
+	quoteTTL   time.Duration    // how long quotes are honoured, defaultQuoteTTL if zero
+	holdTTL    time.Duration    // how long seats are held, defaultHoldTTL if zero
+	payments   PaymentProvider  // takes payment, defaultPayments if nil
+	now        func() time.Time // time.Now if nil
+	// paymentTimeout bounds each charge, defaultPaymentTimeout if zero.
+	paymentTimeout time.Duration
+	// paying has the tokens of holds whose payment is being taken.
+	paying map[string]bool
+	// defaultDeparture is used by requests that do not name a departure.
+	defaultDeparture string
+	// layouts are the configured train layouts, defaultLayouts if nil.
+	layouts *layoutTable
+	// watchers are the WatchAvailability streams.
+	watchers availabilityWatchers
 }
 
 func main() {
+	storeKind := flag.String("store", "memory", "booking storage: memory or file")
+	dataDir := flag.String("data", "data", "directory holding the booking log and snapshots for -store=file")
+	duplicatesFlag := flag.String("duplicates", "reject", "what to do when a user buys a second ticket: reject, allow or replace")
+	pricing := flag.String("pricing", "", "JSON file with the pricing rules, built-in rules if empty")
+	ratesFile := flag.String("rates", "", "JSON file with the exchange rates from the pricing currency, EUR only if empty")
+	quoteTTL := flag.Duration("quote-ttl", defaultQuoteTTL, "how long a quoted fare is honoured")
+	holdTTL := flag.Duration("hold-ttl", defaultHoldTTL, "how long a seat is held for checkout")
+	paymentsFlag := flag.String("payments", "approve", "how the local fake payment provider answers charges: approve, decline or timeout")
+	paymentTimeout := flag.Duration("payment-timeout", defaultPaymentTimeout, "how long the payment provider is given to answer a charge")
+	layoutsFile := flag.String("layouts", "", "JSON file with the coach layouts of trains, the original two section train if empty")
+	idempotencyTTL := flag.Duration("idempotency-ttl", defaultIdempotencyTTL, "how long the outcome of a call with an idempotency key is kept for retries")
+	idempotencyMaxKeys := flag.Int("idempotency-max-keys", defaultIdempotencyMaxKeys, "how many outcomes of calls with an idempotency key are kept, forgetting the oldest first")
+	flag.Parse()
+
+	duplicates, err := parseDuplicatePolicy(*duplicatesFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	paymentOutcome, err := parsePaymentOutcome(*paymentsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var fares *fareTable
+	if *pricing != "" {
+		if fares, err = loadFareTable(*pricing); err != nil {
+			log.Fatalf("failed to load pricing rules: %v", err)
+		}
+	}
+	var rates *exchangeRates
+	if *ratesFile != "" {
+		if rates, err = loadExchangeRates(*ratesFile); err != nil {
+			log.Fatalf("failed to load exchange rates: %v", err)
+		}
+	}
+
+	layouts := &defaultLayouts
+	if *layoutsFile != "" {
+		if layouts, err = loadLayoutTable(*layoutsFile); err != nil {
+			log.Fatalf("failed to load coach layouts: %v", err)
+		}
+	}
+
+	var store BookingStore
+	switch *storeKind {
+	case "memory":
+		store = newMemoryStore()
+	case "file":
+		fileStore, err := openFileStore(*dataDir)
+		if err != nil {
+			log.Fatalf("failed to open booking store: %v", err)
+		}
+		defer fileStore.Close()
+		store = fileStore
+	default:
+		log.Fatalf("unknown store %q, expected memory or file", *storeKind)
+	}
+	// Clients that predate departures book on a train with the default
+	// layout. A stored default departure keeps the layout it was created with.
+	if _, err := store.FindDeparture(defaultDepartureID); err != nil {
+		sections, _ := layouts.sections(layouts.Default)
+		err := store.AddDeparture(&trainService.Departure{
+			Id:       defaultDepartureID,
+			Sections: sections,
+			Layout:   layouts.Default,
+		})
+		if err != nil {
+			log.Fatalf("failed to add the default departure: %v", err)
+		}
+	}
+	consentKey := make([]byte, 32)
+	if _, err := rand.Read(consentKey); err != nil {
+		log.Fatalf("failed to generate consent key: %v", err)
+	}
+	quoteKey := make([]byte, 32)
+	if _, err := rand.Read(quoteKey); err != nil {
+		log.Fatalf("failed to generate quote key: %v", err)
+	}
 	server := &TrainServer{
-		tickets: []*trainService.Ticket{},
-		seatCount: map[string]int{
-			"A": 20,
-			"B": 20,
-		},
+		store:            store,
+		consentKey:       consentKey,
+		duplicates:       duplicates,
+		fares:            fares,
+		rates:            rates,
+		quoteKey:         quoteKey,
+		quoteTTL:         *quoteTTL,
+		holdTTL:          *holdTTL,
+		payments:         newFakePaymentProvider(paymentOutcome),
+		paymentTimeout:   *paymentTimeout,
+		defaultDeparture: defaultDepartureID,
+		layouts:          layouts,
 	}
 
-	grpcServer := grpc.NewServer()
+	// Charges cut short by a crash are settled before their holds can expire.
+	server.settleInterruptedCharges(context.Background())
+
+	// The reaper is stopped before the store is closed.
+	reaperCtx, stopReaper := context.WithCancel(context.Background())
+	reaperDone := make(chan struct{})
+	go func() {
+		server.reapHolds(reaperCtx, holdReapInterval)
+		close(reaperDone)
+	}()
+	defer func() {
+		stopReaper()
+		<-reaperDone
+	}()
+
+	idempotency := newIdempotencyCache(*idempotencyTTL, *idempotencyMaxKeys, time.Now)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(idempotency.intercept))
 	trainService.RegisterTrainServiceServer(grpcServer, server)
 
 	lis, err := net.Listen("tcp", ":50051")
@@ -33,6 +167,17 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
+	// Stop gracefully on SIGINT/SIGTERM so deferred store cleanup runs.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		log.Println("Shutting down server...")
+		// Availability streams never end on their own.
+		server.watchers.close()
+		grpcServer.GracefulStop()
+	}()
+
 	log.Println("Server started on port 50051...")
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
@@ -40,60 +185,168 @@ func main() {
 }
 
 func (s *TrainServer) PurchaseTicket(ctx context.Context, req *trainService.Ticket) (*trainService.Ticket, error) {
+	if err := s.validatePurchase(req); err != nil {
+		return nil, err
+	}
+
+	// The seat is held while the payment is taken, so it is only booked once
+	// the charge succeeds and cannot be sold to anyone else in the meantime.
+	hold, err := s.holdForPayment(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.payForHold(ctx, hold, true)
+}
+
+// validatePurchase checks the fields a purchase needs and normalises its promo
+// code and currency.
+func (s *TrainServer) validatePurchase(req *trainService.Ticket) error {
 	if req == nil {
-		return nil, fmt.Errorf("request is nil")
+		return nilRequest()
 	}
-	if req.From == "" || req.To == "" || req.Section == "" {
-		return nil, fmt.Errorf("(From, To, Section) fields are empty")
+	if err := requireFields("(From, To, Section) fields are empty",
+		field{"from", req.From}, field{"to", req.To}, field{"section", req.Section}); err != nil {
+		return err
 	}
 
 	if req.User == nil {
-		return nil, fmt.Errorf("user info is missing")
+		return invalidField("user", "user info is missing")
 	}
-	if req.User.FirstName == "" || req.User.LastName == "" || req.User.Email == "" {
-		return nil, fmt.Errorf("(FirstName, LastName, Email) fields are empty")
+	if err := requireFields("(FirstName, LastName, Email) fields are empty",
+		field{"user.first_name", req.User.FirstName}, field{"user.last_name", req.User.LastName}, field{"user.email", req.User.Email}); err != nil {
+		return err
 	}
 
-	if s.seatCount[req.Section] > 0 {
-		s.tickets = append(s.tickets, req)
-		s.seatCount[req.Section]--
-		return req, nil
+	req.PromoCode = normalisePromoCode(req.PromoCode)
+	currency, err := s.exchangeRates().currency(req.Currency)
+	if err != nil {
+		return err
 	}
-	return nil, fmt.Errorf("no available seats in section %s", req.Section)
+	req.Currency = currency
+	return nil
 }
 
-func (s *TrainServer) GetReceipt(ctx context.Context, req *trainService.User) (*trainService.Ticket, error) {
-	if req == nil {
-		return nil, fmt.Errorf("request is nil")
+// prepareTicket checks that the journey of req can be booked, prices it and
+// gives it a seat. It returns the ticket req replaces under duplicateReplace,
+// if any. Callers must hold s.mu.
+func (s *TrainServer) prepareTicket(req *trainService.Ticket) (*trainService.Ticket, error) {
+	departure, err := s.findDeparture(req.DepartureId)
+	if err != nil {
+		return nil, err
 	}
-	if req.Email == "" {
-		return nil, fmt.Errorf("email field is empty")
+	if _, ok := sectionLayout(departure, req.Section); !ok {
+		return nil, invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", req.Section, departure.Id))
 	}
+	if err := checkInOpen(departure); err != nil {
+		return nil, err
+	}
+	travelled, err := validateLeg(departure, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConcession(req, departure, s.clock()); err != nil {
+		return nil, err
+	}
+	req.DepartureId = departure.Id
 
-	for _, ticket := range s.tickets {
-		if ticket.User.Email == req.Email {
-			return ticket, nil
+	replaced, err := s.duplicateOf(req)
+	if err != nil {
+		return nil, err
+	}
+	// A replaced ticket gives its seat back, so it counts as free here.
+	replacedReference := ""
+	if replaced != nil {
+		replacedReference = replaced.BookingReference
+	}
+	// The fare is always computed here; any price sent by the client is
+	// ignored.
+	fare, err := s.fare(departure, travelled, req, replacedReference)
+	if err != nil {
+		return nil, err
+	}
+	if free := s.legSeats(departure, req.Section, travelled, replaced); free > 0 {
+		seat, err := s.allocateSeat(departure, req.Section, travelled, req.Seat, req.AccessibleSeat, replacedReference)
+		if err != nil {
+			return nil, err
 		}
+		req.Seat = seat
+	} else if canOverbook(departure, req, free) {
+		// The ticket gets a seat when check-in closes.
+		req.Seat = nil
+	} else {
+		return nil, sectionSoldOut(req.Section)
 	}
-	return nil, fmt.Errorf("ticket not found for user with email: %s", req.Email)
+	req.Price = fare.Price
+	req.OriginalFare = fare.OriginalFare
+	req.Discounts = fare.Discounts
+	req.QuoteToken = ""
+	return replaced, nil
 }
 
-func (s *TrainServer) GetUsersBySection(req *trainService.Ticket, stream trainService.TrainService_GetUsersBySectionServer) error {
+// duplicateOf applies the duplicate policy to a ticket the user of req is
+// about to get on req.DepartureId, returning the ticket to replace, if any.
+// The policy only looks at tickets for the same departure. Callers must hold
+// s.mu.
+func (s *TrainServer) duplicateOf(req *trainService.Ticket) (*trainService.Ticket, error) {
+	existing := departureTickets(s.userTickets(req.User.Email), req.DepartureId)
+	if len(existing) == 0 {
+		return nil, nil
+	}
+	switch s.duplicates {
+	case duplicateReject:
+		return nil, withDetails(codes.AlreadyExists,
+			fmt.Sprintf("user with email %s already holds ticket %s", req.User.Email, existing[0].BookingReference),
+			&errdetails.ResourceInfo{ResourceType: "ticket", ResourceName: existing[0].BookingReference, Owner: req.User.Email})
+	case duplicateReplace:
+		return existing[0], nil
+	}
+	return nil, nil
+}
+
+func (s *TrainServer) GetReceipt(ctx context.Context, req *trainService.User) (*trainService.Ticket, error) {
 	if req == nil {
-		return fmt.Errorf("request is nil")
+		return nil, nilRequest()
 	}
-	if req.Section == "" {
-		return fmt.Errorf("section field is empty")
+	if err := requireFields("email field is empty", field{"email", req.Email}); err != nil {
+		return nil, err
 	}
-	if req.Section != "A" && req.Section != "B" {
-		return fmt.Errorf("only sections A and B are allowed, given section: %v", req.Section)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.firstUserTicket(req.Email)
+}
+
+func (s *TrainServer) GetUsersBySection(req *trainService.Ticket, stream trainService.TrainService_GetUsersBySectionServer) error {
+	if req == nil {
+		return nilRequest()
+	}
+	if err := requireFields("section field is empty", field{"section", req.Section}); err != nil {
+		return err
 	}
 
-	for _, ticket := range s.tickets {
+	// Collect the matching tickets first so a slow stream never holds the lock.
+	s.mu.RLock()
+	departure, err := s.findDeparture(req.DepartureId)
+	if err != nil {
+		s.mu.RUnlock()
+		return err
+	}
+	if _, ok := sectionLayout(departure, req.Section); !ok {
+		s.mu.RUnlock()
+		return invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", req.Section, departure.Id))
+	}
+	var tickets []*trainService.Ticket
+	for _, ticket := range departureTickets(s.store.Tickets(), departure.Id) {
 		if ticket.Section == req.Section {
-			if err := stream.Send(ticket); err != nil {
-				return err
-			}
+			tickets = append(tickets, ticket)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, ticket := range tickets {
+		if err := stream.Send(ticket); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -101,41 +354,110 @@ func (s *TrainServer) GetUsersBySection(req *trainService.Ticket, stream trainSe
 
 func (s *TrainServer) CancelTicket(ctx context.Context, req *trainService.User) (*trainService.Ticket, error) {
 	if req == nil {
-		return nil, fmt.Errorf("request is nil")
+		return nil, nilRequest()
 	}
-	if req.Email == "" {
-		return nil, fmt.Errorf("email field is empty")
+	if err := requireFields("email field is empty", field{"email", req.Email}); err != nil {
+		return nil, err
 	}
 
-	for i, ticket := range s.tickets {
-		if ticket.User.Email == req.Email {
-			s.tickets = append(s.tickets[:i], s.tickets[i+1:]...)
-			s.seatCount[ticket.Section]++
-			return ticket, nil
-		}
+	s.mu.Lock()
+	ticket, err := s.firstUserTicket(req.Email)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
 	}
-	return nil, fmt.Errorf("ticket not found for user with email: %s", req.Email)
+	if _, err := s.store.RemoveTicket(ticket.BookingReference); err != nil {
+		s.mu.Unlock()
+		return nil, internalError("failed to cancel ticket", err)
+	}
+	s.offerWaitlistSeats(ticket.DepartureId)
+	ticket = s.cancelled(ticket)
+	s.mu.Unlock()
+
+	s.refund(ctx, ticket)
+	return ticket, nil
 }
 
 func (s *TrainServer) ModifyUserSeat(ctx context.Context, req *trainService.Ticket) (*trainService.Ticket, error) {
 	if req == nil {
-		return nil, fmt.Errorf("request is nil")
+		return nil, nilRequest()
 	}
-	if req.User == nil {
-		return nil, fmt.Errorf("user is not provided")
+	// The ticket is picked by booking reference, or else the user's first one.
+	if req.BookingReference == "" {
+		if req.User == nil {
+			return nil, invalidField("user", "user is not provided")
+		}
+		if err := requireFields("email field is empty", field{"user.email", req.User.Email}); err != nil {
+			return nil, err
+		}
 	}
-	if req.User.Email == "" {
-		return nil, fmt.Errorf("email field is empty")
+	if err := requireFields("section field is empty", field{"section", req.Section}); err != nil {
+		return nil, err
 	}
-	if req.Section == "" {
-		return nil, fmt.Errorf("section field is empty")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ticket *trainService.Ticket
+	var err error
+	if req.BookingReference != "" {
+		ticket, err = s.store.FindTicket(req.BookingReference)
+		if err != nil {
+			return nil, bookingNotFound(req.BookingReference)
+		}
+	} else {
+		ticket, err = s.firstUserTicket(req.User.Email)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Seats can only change within the ticket's own departure.
+	departure, err := s.store.FindDeparture(ticket.DepartureId)
+	if err != nil {
+		return nil, internalError("failed to load departure", err)
 	}
+	if err := checkInOpen(departure); err != nil {
+		return nil, err
+	}
+	if _, ok := sectionLayout(departure, req.Section); !ok {
+		return nil, invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", req.Section, departure.Id))
+	}
+	travelled := ticketLeg(departure, ticket)
+	updated := proto.Clone(ticket).(*trainService.Ticket)
+	updated.Section = req.Section
 
-	for i, ticket := range s.tickets {
-		if ticket.User.Email == req.User.Email {
-			s.tickets[i].Section = req.Section
-			return s.tickets[i], nil
+	if req.Section == ticket.Section {
+		// Keep the current seat unless another one was asked for.
+		if req.Seat != nil {
+			seat, err := s.allocateSeat(departure, req.Section, travelled, req.Seat, ticket.AccessibleSeat, ticket.BookingReference)
+			if err != nil {
+				return nil, err
+			}
+			updated.Seat = seat
 		}
+		if err := s.store.UpdateTicket(updated); err != nil {
+			return nil, internalError("failed to update ticket", err)
+		}
+		return updated, nil
+	}
+
+	// A seat change keeps the fare class; changing it takes an upgrade.
+	if err := s.checkClassSold(departure, ticket, req.Section); err != nil {
+		return nil, err
+	}
+	if s.legSeats(departure, req.Section, travelled, nil) <= 0 {
+		return nil, sectionSoldOut(req.Section)
+	}
+	seat, err := s.allocateSeat(departure, req.Section, travelled, req.Seat, ticket.AccessibleSeat, ticket.BookingReference)
+	if err != nil {
+		return nil, err
+	}
+	// The passenger keeps the fare they paid; changing what is paid for is
+	// left to UpgradeTicket, which takes the payment.
+	updated.Seat = seat
+	if err := s.store.MoveTicket(updated); err != nil {
+		return nil, internalError("failed to move ticket", err)
 	}
-	return nil, fmt.Errorf("ticket not found for user with email: %s", req.User.Email)
+	s.offerWaitlistSeats(updated.DepartureId)
+	return updated, nil
 }
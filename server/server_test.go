@@ -2,13 +2,36 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/iamir0nman/train/trainService"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
+var testLayout = map[string]seatLayout{
+	"A": {Rows: 5, SeatsPerRow: 4},
+	"B": {Rows: 5, SeatsPerRow: 4},
+}
+
+// newTestStore returns a store holding the departure with the empty ID, which
+// a zero TrainServer books on, laid out as layout. seatCount, if given,
+// overrides the free seats per section.
+func newTestStore(layout map[string]seatLayout, seatCount map[string]int) *memoryStore {
+	store := newMemoryStore()
+	store.AddDeparture(testDeparture("", layout))
+	for section, count := range seatCount {
+		store.seatCount[""][section] = []int{count}
+	}
+	return store
+}
+
 func TestPurchaseTicket(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -31,7 +54,7 @@ func TestPurchaseTicket(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			expectedResp: &trainService.Ticket{
@@ -42,7 +65,7 @@ func TestPurchaseTicket(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			expectedErr: false,
@@ -61,7 +84,7 @@ func TestPurchaseTicket(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "B",
 			},
 			expectedResp: nil,
@@ -76,7 +99,7 @@ func TestPurchaseTicket(t *testing.T) {
 			request: &trainService.Ticket{
 				From:    "London",
 				To:      "Paris",
-				Price:   20,
+				Price:   eur(2000),
 				Section: "B",
 			},
 			expectedResp: nil,
@@ -94,7 +117,7 @@ func TestPurchaseTicket(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			expectedResp: nil,
@@ -102,18 +125,19 @@ func TestPurchaseTicket(t *testing.T) {
 		},
 	}
 
-	server := &TrainServer{
-		tickets: []*trainService.Ticket{},
-	}
+	store := newTestStore(testLayout, nil)
+	server := &TrainServer{store: store}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			server.seatCount = tc.initialSeats
-			numBookedTickets := len(server.tickets)
+			for section, count := range tc.initialSeats {
+				store.seatCount[""][section] = []int{count}
+			}
+			numBookedTickets := len(store.tickets)
 
 			ctx := context.Background()
 			resp, err := server.PurchaseTicket(ctx, tc.request)
 
-			if !tc.expectedErr && numBookedTickets == len(server.tickets) {
+			if !tc.expectedErr && numBookedTickets == len(store.tickets) {
 				t.Error("Number of booked tickets didn't increase")
 			}
 			if tc.expectedErr && err == nil {
@@ -165,14 +189,14 @@ func TestGetReceipt(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			expectedErr: false,
 		},
 	}
 
-	server := &TrainServer{
+	server := &TrainServer{store: &memoryStore{
 		tickets: []*trainService.Ticket{
 			{
 				From: "London",
@@ -182,15 +206,13 @@ func TestGetReceipt(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 		},
-		seatCount: map[string]int{
-			"A": 10,
-			"B": 10,
-		},
-	}
+		departures: []*trainService.Departure{testDeparture("", testLayout)},
+		seatCount:  map[string]map[string][]int{"": {"A": {10}, "B": {10}}},
+	}}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
@@ -256,7 +278,7 @@ func TestGetUsersBySection(t *testing.T) {
 						LastName:  "Kumar",
 						Email:     "deepak@example.com",
 					},
-					Price:   20,
+					Price:   eur(2000),
 					Section: "A",
 				},
 				{
@@ -267,7 +289,7 @@ func TestGetUsersBySection(t *testing.T) {
 						LastName:  "User",
 						Email:     "testuser@example.com",
 					},
-					Price:   20,
+					Price:   eur(2000),
 					Section: "A",
 				},
 			},
@@ -275,7 +297,7 @@ func TestGetUsersBySection(t *testing.T) {
 		},
 	}
 
-	server := &TrainServer{
+	server := &TrainServer{store: &memoryStore{
 		tickets: []*trainService.Ticket{
 			{
 				From: "London",
@@ -285,7 +307,7 @@ func TestGetUsersBySection(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			{
@@ -296,15 +318,13 @@ func TestGetUsersBySection(t *testing.T) {
 					LastName:  "User",
 					Email:     "testuser@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 		},
-		seatCount: map[string]int{
-			"A": 10,
-			"B": 10,
-		},
-	}
+		departures: []*trainService.Departure{testDeparture("", testLayout)},
+		seatCount:  map[string]map[string][]int{"": {"A": {10}, "B": {10}}},
+	}}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -366,14 +386,14 @@ func TestCancelTicket(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			expectedErr: false,
 		},
 	}
 
-	server := &TrainServer{
+	server := &TrainServer{store: &memoryStore{
 		tickets: []*trainService.Ticket{
 			{
 				From: "London",
@@ -383,15 +403,13 @@ func TestCancelTicket(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 		},
-		seatCount: map[string]int{
-			"A": 10,
-			"B": 10,
-		},
-	}
+		departures: []*trainService.Departure{testDeparture("", testLayout)},
+		seatCount:  map[string]map[string][]int{"": {"A": {10}, "B": {10}}},
+	}}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
@@ -464,14 +482,14 @@ func TestModifyUserSeat(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 			expectedErr: false,
 		},
 	}
 
-	server := &TrainServer{
+	server := &TrainServer{store: &memoryStore{
 		tickets: []*trainService.Ticket{
 			{
 				From: "London",
@@ -481,15 +499,13 @@ func TestModifyUserSeat(t *testing.T) {
 					LastName:  "Kumar",
 					Email:     "deepak@example.com",
 				},
-				Price:   20,
+				Price:   eur(2000),
 				Section: "A",
 			},
 		},
-		seatCount: map[string]int{
-			"A": 10,
-			"B": 10,
-		},
-	}
+		departures: []*trainService.Departure{testDeparture("", testLayout)},
+		seatCount:  map[string]map[string][]int{"": {"A": {10}, "B": {10}}},
+	}}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
@@ -501,9 +517,296 @@ func TestModifyUserSeat(t *testing.T) {
 			if !tc.expectedErr && err != nil {
 				t.Errorf("Unexpected error: %v", err)
 			}
-			if !tc.expectedErr && !reflect.DeepEqual(tc.expectedResp, resp) {
+			if !tc.expectedErr && !proto.Equal(tc.expectedResp, resp) {
 				t.Errorf("Expected ticket: %v,\n got ticket: %v", tc.expectedResp, resp)
 			}
 		})
 	}
 }
+
+func TestModifyUserSeatCapacity(t *testing.T) {
+	store := newTestStore(map[string]seatLayout{
+		"A": {Rows: 1, SeatsPerRow: 2},
+		"B": {Rows: 1, SeatsPerRow: 2},
+	}, map[string]int{"A": 1, "B": 1})
+	server := &TrainServer{store: store}
+	ctx := context.Background()
+
+	for _, ticket := range []*trainService.Ticket{
+		testTicket("deepak@example.com", "A"),
+		testTicket("test@example.com", "B"),
+	} {
+		if _, err := server.PurchaseTicket(ctx, ticket); err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+	}
+
+	move := func(email, section string) error {
+		_, err := server.ModifyUserSeat(ctx, &trainService.Ticket{
+			User:    &trainService.User{Email: email},
+			Section: section,
+		})
+		return err
+	}
+	assertSeats := func(a, b int) {
+		t.Helper()
+		if store.SeatCount("", "A") != a || store.SeatCount("", "B") != b {
+			t.Errorf("Expected %d seats in A and %d in B, got %d and %d", a, b, store.SeatCount("", "A"), store.SeatCount("", "B"))
+		}
+	}
+
+	if err := move("deepak@example.com", "C"); err == nil {
+		t.Error("Expected an error moving to an unknown section, got nil")
+	}
+	if err := move("deepak@example.com", "B"); err == nil {
+		t.Error("Expected an error moving to a full section, got nil")
+	}
+	assertSeats(0, 0)
+	if ticket, _ := server.GetReceipt(ctx, &trainService.User{Email: "deepak@example.com"}); ticket.Section != "A" {
+		t.Errorf("Failed move changed the ticket: %v", ticket)
+	}
+
+	if _, err := server.CancelTicket(ctx, &trainService.User{Email: "test@example.com"}); err != nil {
+		t.Fatalf("CancelTicket failed: %v", err)
+	}
+	if err := move("deepak@example.com", "B"); err != nil {
+		t.Fatalf("ModifyUserSeat failed: %v", err)
+	}
+	assertSeats(1, 0)
+	if ticket, _ := server.GetReceipt(ctx, &trainService.User{Email: "deepak@example.com"}); ticket.Section != "B" {
+		t.Errorf("Expected the ticket in section B, got %v", ticket)
+	}
+}
+
+func TestModifyUserSeatKeepsFare(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections[1].FareFactor = 1.25
+	departure := createDeparture(t, server, req)
+	purchase := func(email, section string) *trainService.Ticket {
+		ticket := testJourney(email, departure.Id, section)
+		booked, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		return booked
+	}
+
+	booked := purchase("deepak@example.com", "A")
+	if dearer := purchase("test@example.com", "B"); dearer.Price.MinorUnits <= booked.Price.MinorUnits {
+		t.Fatalf("Expected section B to cost more than %v, got %v", booked.Price, dearer.Price)
+	}
+	moved, err := server.ModifyUserSeat(ctx, &trainService.Ticket{BookingReference: booked.BookingReference, Section: "B"})
+	if err != nil {
+		t.Fatalf("ModifyUserSeat failed: %v", err)
+	}
+	if moved.Section != "B" || !proto.Equal(moved.Price, booked.Price) || !proto.Equal(moved.OriginalFare, booked.OriginalFare) ||
+		!proto.Equal(moved.AmountPaid, booked.AmountPaid) {
+		t.Errorf("Expected the ticket moved to B at the fare paid %v, got %v", booked.Price, moved)
+	}
+}
+
+func TestModifyUserSeatAcrossCabins(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts, duplicates: duplicateReject}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	ticket := testJourney("deepak@example.com", departure.Id, "S")
+	booked, err := server.PurchaseTicket(ctx, ticket)
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+
+	// A standard ticket only gets into the first class coach by an upgrade.
+	_, err = server.ModifyUserSeat(ctx, &trainService.Ticket{BookingReference: booked.BookingReference, Section: "F"})
+	if status.Code(err) != codes.FailedPrecondition || !strings.Contains(status.Convert(err).Message(), "UpgradeTicket") {
+		t.Fatalf("Expected FailedPrecondition pointing to UpgradeTicket, got %v", err)
+	}
+	if stored, _ := server.store.FindTicket(booked.BookingReference); !proto.Equal(stored, booked) {
+		t.Errorf("Expected the ticket unchanged, got %v", stored)
+	}
+}
+
+func TestConcurrentRPCs(t *testing.T) {
+	const (
+		seatsPerSection = 25
+		buyers          = 120
+	)
+
+	store := newTestStore(map[string]seatLayout{
+		"A": {Rows: 5, SeatsPerRow: 5},
+		"B": {Rows: 5, SeatsPerRow: 5},
+	}, nil)
+	server := &TrainServer{store: store}
+
+	sections := []string{"A", "B"}
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		purchased int
+		cancelled int
+	)
+	for i := 0; i < buyers; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		section := sections[i%2]
+		cancel := i%3 == 0
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.Background()
+
+			_, err := server.PurchaseTicket(ctx, &trainService.Ticket{
+				From: "London",
+				To:   "Paris",
+				User: &trainService.User{
+					FirstName: "Test",
+					LastName:  "User",
+					Email:     email,
+				},
+				Price:   eur(2000),
+				Section: section,
+			})
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			purchased++
+			mu.Unlock()
+
+			if _, err := server.GetReceipt(ctx, &trainService.User{Email: email}); err != nil {
+				t.Errorf("GetReceipt after purchase failed: %v", err)
+			}
+			if err := server.GetUsersBySection(&trainService.Ticket{Section: section}, &mockStream{}); err != nil {
+				t.Errorf("GetUsersBySection failed: %v", err)
+			}
+			// Moving fails once the other section has no free seat left,
+			// which is fine as long as the booking state stays consistent.
+			server.ModifyUserSeat(ctx, &trainService.Ticket{
+				User:    &trainService.User{Email: email},
+				Section: sections[len(email)%2],
+			})
+			if cancel {
+				if _, err := server.CancelTicket(ctx, &trainService.User{Email: email}); err != nil {
+					t.Errorf("CancelTicket failed: %v", err)
+				}
+				mu.Lock()
+				cancelled++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if purchased-cancelled > 2*seatsPerSection {
+		t.Errorf("Holding %d tickets for %d seats", purchased-cancelled, 2*seatsPerSection)
+	}
+	if len(store.tickets) != purchased-cancelled {
+		t.Errorf("Expected %d booked tickets, got %d", purchased-cancelled, len(store.tickets))
+	}
+	booked := map[string]int{}
+	for _, ticket := range store.tickets {
+		booked[ticket.Section]++
+	}
+	for _, section := range sections {
+		if store.SeatCount("", section) < 0 {
+			t.Errorf("Seat count for section %s went negative: %d", section, store.SeatCount("", section))
+		}
+		if store.SeatCount("", section)+booked[section] != seatsPerSection {
+			t.Errorf("Seats leaked in section %s: %d free + %d booked != %d", section, store.SeatCount("", section), booked[section], seatsPerSection)
+		}
+	}
+
+	seen := map[string]bool{}
+	seats := map[string]bool{}
+	for _, ticket := range store.tickets {
+		if seen[ticket.User.Email] {
+			t.Errorf("Duplicate ticket for %s", ticket.User.Email)
+		}
+		seen[ticket.User.Email] = true
+
+		seat := ticket.Section + " " + seatLabel(ticket.Seat)
+		if seats[seat] {
+			t.Errorf("Seat %s sold twice", seat)
+		}
+		seats[seat] = true
+	}
+}
+
+func TestSeatAssignment(t *testing.T) {
+	store := newTestStore(map[string]seatLayout{
+		"A": {Rows: 2, SeatsPerRow: 2},
+		"B": {Rows: 2, SeatsPerRow: 2},
+	}, map[string]int{"A": 3, "B": 4})
+	server := &TrainServer{store: store}
+	ctx := context.Background()
+
+	purchase := func(email, section string, seat *trainService.Seat) (*trainService.Ticket, error) {
+		ticket := testTicket(email, section)
+		ticket.Seat = seat
+		return server.PurchaseTicket(ctx, ticket)
+	}
+
+	first, err := purchase("first@example.com", "A", nil)
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	if !proto.Equal(first.Seat, &trainService.Seat{Row: 1, Number: 1}) {
+		t.Errorf("Expected the first free seat, got %v", first.Seat)
+	}
+
+	chosen, err := purchase("chosen@example.com", "A", &trainService.Seat{Row: 2, Number: 1})
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	if !proto.Equal(chosen.Seat, &trainService.Seat{Row: 2, Number: 1}) {
+		t.Errorf("Expected the requested seat, got %v", chosen.Seat)
+	}
+
+	if _, err := purchase("taken@example.com", "A", &trainService.Seat{Row: 2, Number: 1}); err == nil {
+		t.Error("Expected an error buying a taken seat, got nil")
+	}
+	if _, err := purchase("missing@example.com", "A", &trainService.Seat{Row: 3, Number: 1}); err == nil {
+		t.Error("Expected an error buying a seat outside the seat map, got nil")
+	}
+
+	next, err := purchase("next@example.com", "A", nil)
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	if !proto.Equal(next.Seat, &trainService.Seat{Row: 1, Number: 2}) {
+		t.Errorf("Expected the next free seat, got %v", next.Seat)
+	}
+
+	receipt, err := server.GetReceipt(ctx, &trainService.User{Email: "chosen@example.com"})
+	if err != nil {
+		t.Fatalf("GetReceipt failed: %v", err)
+	}
+	if !proto.Equal(receipt.Seat, chosen.Seat) {
+		t.Errorf("Expected the receipt to show %v, got %v", chosen.Seat, receipt.Seat)
+	}
+
+	moved, err := server.ModifyUserSeat(ctx, &trainService.Ticket{
+		User:    &trainService.User{Email: "first@example.com"},
+		Section: "B",
+	})
+	if err != nil {
+		t.Fatalf("ModifyUserSeat failed: %v", err)
+	}
+	if !proto.Equal(moved.Seat, &trainService.Seat{Row: 1, Number: 1}) {
+		t.Errorf("Expected a seat in section B, got %v", moved.Seat)
+	}
+
+	stream := &mockStream{}
+	if err := server.GetUsersBySection(&trainService.Ticket{Section: "B"}, stream); err != nil {
+		t.Fatalf("GetUsersBySection failed: %v", err)
+	}
+	if len(stream.data) != 1 || !proto.Equal(stream.data[0].Seat, moved.Seat) {
+		t.Errorf("Expected section B to list %v, got %v", moved.Seat, stream.data)
+	}
+}
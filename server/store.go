@@ -0,0 +1,607 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	errTicketNotFound    = errors.New("ticket not found")
+	errDepartureNotFound = errors.New("departure not found")
+	errDepartureExists   = errors.New("departure already exists")
+	errStationExists     = errors.New("station already exists")
+	errRouteNotFound     = errors.New("route not found")
+	errRouteExists       = errors.New("route already exists")
+	errPromoCodeNotFound = errors.New("promo code not found")
+	errPromoCodeExists   = errors.New("promo code already exists")
+	errHoldNotFound      = errors.New("seat hold not found")
+	errWaitlistNotFound  = errors.New("waitlist entry not found")
+	errUpgradeNotFound   = errors.New("pending upgrade not found")
+)
+
+// BookingStore keeps the station network, the scheduled departures, the promo
+// codes, the booked tickets, the seats held for checkout, the upgrades being
+// charged, the waitlist and the remaining seats per section and route segment
+// of each departure.
+// TrainServer serialises access with its own lock, so implementations only
+// need each call to be applied as a whole or not at all.
+type BookingStore interface {
+	// Stations returns the stations in the order they were added.
+	Stations() []*trainService.Station
+	AddStation(station *trainService.Station) error
+	// Routes returns the routes in the order they were added.
+	Routes() []*trainService.Route
+	FindRoute(id string) (*trainService.Route, error)
+	AddRoute(route *trainService.Route) error
+
+	// Departures returns the departures in the order they were added.
+	Departures() []*trainService.Departure
+	FindDeparture(id string) (*trainService.Departure, error)
+	// AddDeparture records departure with every seat of its sections free.
+	AddDeparture(departure *trainService.Departure) error
+	// UpdateDeparture replaces the departure with the same ID, changing the
+	// free seats of each section by the change in its capacity.
+	UpdateDeparture(departure *trainService.Departure) error
+
+	// PromoCodes returns the promo codes in the order they were added.
+	PromoCodes() []*trainService.PromoCode
+	FindPromoCode(code string) (*trainService.PromoCode, error)
+	AddPromoCode(promo *trainService.PromoCode) error
+
+	// Tickets returns the booked tickets in booking order.
+	Tickets() []*trainService.Ticket
+	// FindTicket returns the ticket with the given booking reference.
+	FindTicket(reference string) (*trainService.Ticket, error)
+	// SeatCount returns the seats in a section of a departure that are free on
+	// every segment of the route.
+	SeatCount(departure, section string) int
+	// SegmentSeatCounts returns the free seats in a section of a departure
+	// for each segment of the route, segment i running from stop i to i+1.
+	SegmentSeatCounts(departure, section string) []int
+	// AddTicket records ticket and takes a seat from its section on each
+	// segment between the ticket's stations.
+	AddTicket(ticket *trainService.Ticket) error
+	// RemoveTicket deletes a ticket and gives its seat back to the section.
+	RemoveTicket(reference string) (*trainService.Ticket, error)
+	// UpdateTicket replaces the ticket with the same booking reference.
+	UpdateTicket(ticket *trainService.Ticket) error
+	// MoveTicket replaces the ticket with the same booking reference by one in
+	// another section, giving a seat back to the old section and taking one
+	// from the new one.
+	MoveTicket(ticket *trainService.Ticket) error
+	// ReplaceTicket deletes the ticket with the given booking reference and
+	// records ticket instead, moving the seat counts accordingly.
+	ReplaceTicket(reference string, ticket *trainService.Ticket) error
+	// SwapSeats exchanges the section and seat of two tickets. Seat counts are
+	// unchanged.
+	SwapSeats(reference, otherReference string) error
+
+	// Holds returns the seat holds in the order they were placed.
+	Holds() []*trainService.SeatHold
+	FindHold(token string) (*trainService.SeatHold, error)
+	// AddHold records hold and takes a seat for its ticket as AddTicket does.
+	AddHold(hold *trainService.SeatHold) error
+	// RemoveHold deletes a hold and gives its seat back.
+	RemoveHold(token string) (*trainService.SeatHold, error)
+	// UpdateHold replaces the hold with the same token in place. The held
+	// ticket must not change.
+	UpdateHold(hold *trainService.SeatHold) error
+	// ConfirmHold deletes the hold with the given token and records ticket in
+	// its place. If replaced is not empty, the ticket with that booking
+	// reference is deleted first.
+	ConfirmHold(token, replaced string, ticket *trainService.Ticket) error
+	// AddHolds records every hold, as AddHold does, or none of them.
+	AddHolds(holds []*trainService.SeatHold) error
+	// ConfirmHolds confirms each hold in tokens with the ticket at the same
+	// index, as ConfirmHold does, or none of them.
+	ConfirmHolds(tokens []string, tickets []*trainService.Ticket) error
+	// SettleDeparture replaces the departure with the same ID, replaces each
+	// ticket in tickets as MoveTicket does, possibly moving it to another
+	// departure, and deletes the tickets with the booking references in
+	// cancelled, all or none of it.
+	SettleDeparture(departure *trainService.Departure, tickets []*trainService.Ticket, cancelled []string) error
+
+	// PendingUpgrades returns the upgrades being charged in the order they
+	// started.
+	PendingUpgrades() []*trainService.PendingUpgrade
+	AddPendingUpgrade(upgrade *trainService.PendingUpgrade) error
+	// RemovePendingUpgrade deletes the upgrade with the given charge key.
+	RemovePendingUpgrade(key string) error
+
+	// Waitlist returns the waitlist entries in the order they joined.
+	Waitlist() []*trainService.WaitlistEntry
+	FindWaitlistEntry(id string) (*trainService.WaitlistEntry, error)
+	AddWaitlistEntry(entry *trainService.WaitlistEntry) error
+	RemoveWaitlistEntry(id string) (*trainService.WaitlistEntry, error)
+	// UpdateWaitlistEntry replaces the entry with the same ID in place.
+	UpdateWaitlistEntry(entry *trainService.WaitlistEntry) error
+}
+
+type memoryStore struct {
+	stations   []*trainService.Station
+	routes     []*trainService.Route
+	departures []*trainService.Departure
+	promoCodes []*trainService.PromoCode
+	tickets    []*trainService.Ticket
+	holds      []*trainService.SeatHold
+	upgrades   []*trainService.PendingUpgrade
+	waitlist   []*trainService.WaitlistEntry
+	seatCount  map[string]map[string][]int // departure ID -> section -> free seats per segment
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		tickets:   []*trainService.Ticket{},
+		seatCount: map[string]map[string][]int{},
+	}
+}
+
+func (m *memoryStore) Stations() []*trainService.Station {
+	return append([]*trainService.Station(nil), m.stations...)
+}
+
+func (m *memoryStore) AddStation(station *trainService.Station) error {
+	for _, existing := range m.stations {
+		if existing.Code == station.Code {
+			return errStationExists
+		}
+	}
+	m.stations = append(m.stations, station)
+	return nil
+}
+
+func (m *memoryStore) Routes() []*trainService.Route {
+	return append([]*trainService.Route(nil), m.routes...)
+}
+
+func (m *memoryStore) FindRoute(id string) (*trainService.Route, error) {
+	for _, route := range m.routes {
+		if route.Id == id {
+			return route, nil
+		}
+	}
+	return nil, errRouteNotFound
+}
+
+func (m *memoryStore) AddRoute(route *trainService.Route) error {
+	if _, err := m.FindRoute(route.Id); err == nil {
+		return errRouteExists
+	}
+	m.routes = append(m.routes, route)
+	return nil
+}
+
+func (m *memoryStore) Departures() []*trainService.Departure {
+	return append([]*trainService.Departure(nil), m.departures...)
+}
+
+func (m *memoryStore) FindDeparture(id string) (*trainService.Departure, error) {
+	for _, departure := range m.departures {
+		if departure.Id == id {
+			return departure, nil
+		}
+	}
+	return nil, errDepartureNotFound
+}
+
+func (m *memoryStore) AddDeparture(departure *trainService.Departure) error {
+	if _, err := m.FindDeparture(departure.Id); err == nil {
+		return errDepartureExists
+	}
+	seatCount := map[string][]int{}
+	for _, section := range departure.Sections {
+		free := make([]int, segmentCount(departure))
+		for i := range free {
+			free[i] = int(section.Rows * section.SeatsPerRow)
+		}
+		seatCount[section.Section] = free
+	}
+	m.departures = append(m.departures, departure)
+	m.seatCount[departure.Id] = seatCount
+	return nil
+}
+
+func (m *memoryStore) UpdateDeparture(departure *trainService.Departure) error {
+	i := -1
+	for j, existing := range m.departures {
+		if existing.Id == departure.Id {
+			i = j
+		}
+	}
+	if i < 0 {
+		return errDepartureNotFound
+	}
+	capacity := map[string]int{}
+	for _, section := range m.departures[i].Sections {
+		capacity[section.Section] = int(section.Rows * section.SeatsPerRow)
+	}
+	seatCount := m.seatCount[departure.Id]
+	for _, section := range departure.Sections {
+		delta := int(section.Rows*section.SeatsPerRow) - capacity[section.Section]
+		free := seatCount[section.Section]
+		if free == nil {
+			free = make([]int, segmentCount(departure))
+			seatCount[section.Section] = free
+		}
+		for j := range free {
+			free[j] += delta
+		}
+	}
+	m.departures[i] = departure
+	return nil
+}
+
+func (m *memoryStore) PromoCodes() []*trainService.PromoCode {
+	return append([]*trainService.PromoCode(nil), m.promoCodes...)
+}
+
+func (m *memoryStore) FindPromoCode(code string) (*trainService.PromoCode, error) {
+	for _, promo := range m.promoCodes {
+		if promo.Code == code {
+			return promo, nil
+		}
+	}
+	return nil, errPromoCodeNotFound
+}
+
+func (m *memoryStore) AddPromoCode(promo *trainService.PromoCode) error {
+	if _, err := m.FindPromoCode(promo.Code); err == nil {
+		return errPromoCodeExists
+	}
+	m.promoCodes = append(m.promoCodes, promo)
+	return nil
+}
+
+func (m *memoryStore) Tickets() []*trainService.Ticket {
+	return append([]*trainService.Ticket(nil), m.tickets...)
+}
+
+func (m *memoryStore) FindTicket(reference string) (*trainService.Ticket, error) {
+	i := m.indexOf(reference)
+	if i < 0 {
+		return nil, errTicketNotFound
+	}
+	return m.tickets[i], nil
+}
+
+func (m *memoryStore) SeatCount(departure, section string) int {
+	free := m.seatCount[departure][section]
+	if len(free) == 0 {
+		return 0
+	}
+	return leg{0, len(free)}.freeSeats(free)
+}
+
+func (m *memoryStore) SegmentSeatCounts(departure, section string) []int {
+	return append([]int(nil), m.seatCount[departure][section]...)
+}
+
+func (m *memoryStore) AddTicket(ticket *trainService.Ticket) error {
+	travelled, err := m.legOf(ticket)
+	if err != nil {
+		return err
+	}
+	m.tickets = append(m.tickets, ticket)
+	m.takeSeat(ticket, travelled, -1)
+	return nil
+}
+
+func (m *memoryStore) RemoveTicket(reference string) (*trainService.Ticket, error) {
+	i := m.indexOf(reference)
+	if i < 0 {
+		return nil, errTicketNotFound
+	}
+	ticket := m.tickets[i]
+	travelled, err := m.legOf(ticket)
+	if err != nil {
+		return nil, err
+	}
+	m.tickets = append(m.tickets[:i:i], m.tickets[i+1:]...)
+	m.takeSeat(ticket, travelled, 1)
+	return ticket, nil
+}
+
+func (m *memoryStore) UpdateTicket(ticket *trainService.Ticket) error {
+	i := m.indexOf(ticket.BookingReference)
+	if i < 0 {
+		return errTicketNotFound
+	}
+	m.tickets[i] = ticket
+	return nil
+}
+
+func (m *memoryStore) MoveTicket(ticket *trainService.Ticket) error {
+	i := m.indexOf(ticket.BookingReference)
+	if i < 0 {
+		return errTicketNotFound
+	}
+	travelled, err := m.legOf(ticket)
+	if err != nil {
+		return err
+	}
+	old := m.tickets[i]
+	oldTravelled, err := m.legOf(old)
+	if err != nil {
+		return err
+	}
+	m.takeSeat(old, oldTravelled, 1)
+	m.takeSeat(ticket, travelled, -1)
+	m.tickets[i] = ticket
+	return nil
+}
+
+func (m *memoryStore) ReplaceTicket(reference string, ticket *trainService.Ticket) error {
+	if _, err := m.legOf(ticket); err != nil {
+		return err
+	}
+	if _, err := m.RemoveTicket(reference); err != nil {
+		return err
+	}
+	return m.AddTicket(ticket)
+}
+
+func (m *memoryStore) SwapSeats(reference, otherReference string) error {
+	i, j := m.indexOf(reference), m.indexOf(otherReference)
+	if i < 0 || j < 0 {
+		return errTicketNotFound
+	}
+	first := proto.Clone(m.tickets[i]).(*trainService.Ticket)
+	second := proto.Clone(m.tickets[j]).(*trainService.Ticket)
+	first.Section, second.Section = second.Section, first.Section
+	first.Seat, second.Seat = second.Seat, first.Seat
+	m.tickets[i], m.tickets[j] = first, second
+	return nil
+}
+
+func (m *memoryStore) Holds() []*trainService.SeatHold {
+	return append([]*trainService.SeatHold(nil), m.holds...)
+}
+
+func (m *memoryStore) FindHold(token string) (*trainService.SeatHold, error) {
+	i := m.holdIndexOf(token)
+	if i < 0 {
+		return nil, errHoldNotFound
+	}
+	return m.holds[i], nil
+}
+
+func (m *memoryStore) AddHold(hold *trainService.SeatHold) error {
+	travelled, err := m.legOf(hold.Ticket)
+	if err != nil {
+		return err
+	}
+	m.holds = append(m.holds, hold)
+	m.takeSeat(hold.Ticket, travelled, -1)
+	return nil
+}
+
+func (m *memoryStore) RemoveHold(token string) (*trainService.SeatHold, error) {
+	i := m.holdIndexOf(token)
+	if i < 0 {
+		return nil, errHoldNotFound
+	}
+	hold := m.holds[i]
+	travelled, err := m.legOf(hold.Ticket)
+	if err != nil {
+		return nil, err
+	}
+	m.holds = append(m.holds[:i:i], m.holds[i+1:]...)
+	m.takeSeat(hold.Ticket, travelled, 1)
+	return hold, nil
+}
+
+func (m *memoryStore) UpdateHold(hold *trainService.SeatHold) error {
+	i := m.holdIndexOf(hold.Token)
+	if i < 0 {
+		return errHoldNotFound
+	}
+	m.holds[i] = hold
+	return nil
+}
+
+func (m *memoryStore) ConfirmHold(token, replaced string, ticket *trainService.Ticket) error {
+	if m.holdIndexOf(token) < 0 {
+		return errHoldNotFound
+	}
+	if replaced != "" && m.indexOf(replaced) < 0 {
+		return errTicketNotFound
+	}
+	if _, err := m.legOf(ticket); err != nil {
+		return err
+	}
+	if _, err := m.RemoveHold(token); err != nil {
+		return err
+	}
+	if replaced != "" {
+		if _, err := m.RemoveTicket(replaced); err != nil {
+			return err
+		}
+	}
+	return m.AddTicket(ticket)
+}
+
+func (m *memoryStore) AddHolds(holds []*trainService.SeatHold) error {
+	for _, hold := range holds {
+		if _, err := m.legOf(hold.Ticket); err != nil {
+			return err
+		}
+	}
+	for _, hold := range holds {
+		if err := m.AddHold(hold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) ConfirmHolds(tokens []string, tickets []*trainService.Ticket) error {
+	if err := m.checkConfirmHolds(tokens, tickets); err != nil {
+		return err
+	}
+	for i, token := range tokens {
+		if err := m.ConfirmHold(token, "", tickets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkConfirmHolds reports why ConfirmHolds would fail, if it would.
+func (m *memoryStore) checkConfirmHolds(tokens []string, tickets []*trainService.Ticket) error {
+	if len(tokens) != len(tickets) {
+		return fmt.Errorf("%d seat holds cannot be confirmed with %d tickets", len(tokens), len(tickets))
+	}
+	for i, token := range tokens {
+		if m.holdIndexOf(token) < 0 {
+			return errHoldNotFound
+		}
+		if _, err := m.legOf(tickets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) SettleDeparture(departure *trainService.Departure, tickets []*trainService.Ticket, cancelled []string) error {
+	if err := m.checkSettleDeparture(departure, tickets, cancelled); err != nil {
+		return err
+	}
+	if err := m.UpdateDeparture(departure); err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		if err := m.MoveTicket(ticket); err != nil {
+			return err
+		}
+	}
+	for _, reference := range cancelled {
+		if _, err := m.RemoveTicket(reference); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSettleDeparture reports why SettleDeparture would fail, if it would.
+func (m *memoryStore) checkSettleDeparture(departure *trainService.Departure, tickets []*trainService.Ticket, cancelled []string) error {
+	if _, err := m.FindDeparture(departure.Id); err != nil {
+		return err
+	}
+	for _, ticket := range tickets {
+		if m.indexOf(ticket.BookingReference) < 0 {
+			return errTicketNotFound
+		}
+		if _, err := m.legOf(ticket); err != nil {
+			return err
+		}
+	}
+	for _, reference := range cancelled {
+		if m.indexOf(reference) < 0 {
+			return errTicketNotFound
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) PendingUpgrades() []*trainService.PendingUpgrade {
+	return append([]*trainService.PendingUpgrade(nil), m.upgrades...)
+}
+
+func (m *memoryStore) AddPendingUpgrade(upgrade *trainService.PendingUpgrade) error {
+	m.upgrades = append(m.upgrades, upgrade)
+	return nil
+}
+
+func (m *memoryStore) RemovePendingUpgrade(key string) error {
+	for i, upgrade := range m.upgrades {
+		if upgrade.ChargeKey == key {
+			m.upgrades = append(m.upgrades[:i:i], m.upgrades[i+1:]...)
+			return nil
+		}
+	}
+	return errUpgradeNotFound
+}
+
+func (m *memoryStore) Waitlist() []*trainService.WaitlistEntry {
+	return append([]*trainService.WaitlistEntry(nil), m.waitlist...)
+}
+
+func (m *memoryStore) FindWaitlistEntry(id string) (*trainService.WaitlistEntry, error) {
+	i := m.waitlistIndexOf(id)
+	if i < 0 {
+		return nil, errWaitlistNotFound
+	}
+	return m.waitlist[i], nil
+}
+
+func (m *memoryStore) AddWaitlistEntry(entry *trainService.WaitlistEntry) error {
+	m.waitlist = append(m.waitlist, entry)
+	return nil
+}
+
+func (m *memoryStore) RemoveWaitlistEntry(id string) (*trainService.WaitlistEntry, error) {
+	i := m.waitlistIndexOf(id)
+	if i < 0 {
+		return nil, errWaitlistNotFound
+	}
+	entry := m.waitlist[i]
+	m.waitlist = append(m.waitlist[:i:i], m.waitlist[i+1:]...)
+	return entry, nil
+}
+
+func (m *memoryStore) UpdateWaitlistEntry(entry *trainService.WaitlistEntry) error {
+	i := m.waitlistIndexOf(entry.Id)
+	if i < 0 {
+		return errWaitlistNotFound
+	}
+	m.waitlist[i] = entry
+	return nil
+}
+
+// legOf returns the segments ticket travels on within its departure.
+func (m *memoryStore) legOf(ticket *trainService.Ticket) (leg, error) {
+	departure, err := m.FindDeparture(ticket.DepartureId)
+	if err != nil {
+		return leg{}, err
+	}
+	return legOf(departure, ticket.From, ticket.To)
+}
+
+// takeSeat adds delta to the free seats of the ticket's section on each
+// segment of travelled.
+func (m *memoryStore) takeSeat(ticket *trainService.Ticket, travelled leg, delta int) {
+	free := m.seatCount[ticket.DepartureId][ticket.Section]
+	for i := travelled.first; i < travelled.last && i < len(free); i++ {
+		free[i] += delta
+	}
+}
+
+func (m *memoryStore) waitlistIndexOf(id string) int {
+	for i, entry := range m.waitlist {
+		if entry.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *memoryStore) holdIndexOf(token string) int {
+	for i, hold := range m.holds {
+		if hold.Token == token {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *memoryStore) indexOf(reference string) int {
+	for i, ticket := range m.tickets {
+		if ticket.BookingReference == reference {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,630 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/protobuf/proto"
+)
+
+// testTicket returns a ticket whose booking reference is the upper-cased local
+// part of email, e.g. DEEPAK for deepak@example.com.
+func testTicket(email, section string) *trainService.Ticket {
+	reference, _, _ := strings.Cut(email, "@")
+	return &trainService.Ticket{
+		BookingReference: strings.ToUpper(reference),
+		From:             "London",
+		To:               "Paris",
+		User: &trainService.User{
+			FirstName: "Test",
+			LastName:  "User",
+			Email:     email,
+		},
+		Price:   eur(2000),
+		Section: section,
+	}
+}
+
+// eur returns an amount of euros, the default pricing currency.
+func eur(minorUnits int64) *trainService.Money {
+	return &trainService.Money{CurrencyCode: "EUR", MinorUnits: minorUnits}
+}
+
+// testDeparture returns a departure with the given ID and seat maps.
+func testDeparture(id string, layout map[string]seatLayout) *trainService.Departure {
+	departure := &trainService.Departure{Id: id}
+	for section, sectionLayout := range layout {
+		departure.Sections = append(departure.Sections, &trainService.SectionCapacity{
+			Section:     section,
+			Rows:        sectionLayout.Rows,
+			SeatsPerRow: sectionLayout.SeatsPerRow,
+		})
+	}
+	sort.Slice(departure.Sections, func(i, j int) bool {
+		return departure.Sections[i].Section < departure.Sections[j].Section
+	})
+	return departure
+}
+
+// openTestFileStore opens a file store in dir and adds the departure with
+// the empty ID laid out as testLayout.
+func openTestFileStore(t *testing.T, dir string) *fileStore {
+	t.Helper()
+	store, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.AddDeparture(testDeparture("", testLayout)); err != nil {
+		t.Fatalf("AddDeparture failed: %v", err)
+	}
+	return store
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) BookingStore{
+		"memory": func(t *testing.T) BookingStore {
+			return newMemoryStore()
+		},
+		"file": func(t *testing.T) BookingStore {
+			store, err := openFileStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			if err := store.AddDeparture(testDeparture("", map[string]seatLayout{
+				"A": {Rows: 1, SeatsPerRow: 2},
+				"B": {Rows: 1, SeatsPerRow: 2},
+			})); err != nil {
+				t.Fatalf("AddDeparture failed: %v", err)
+			}
+
+			if err := store.AddTicket(testTicket("deepak@example.com", "A")); err != nil {
+				t.Fatalf("AddTicket failed: %v", err)
+			}
+			if err := store.AddTicket(testTicket("test@example.com", "B")); err != nil {
+				t.Fatalf("AddTicket failed: %v", err)
+			}
+			if store.SeatCount("", "A") != 1 || store.SeatCount("", "B") != 1 {
+				t.Errorf("Expected 1 seat left in A and B, got %d and %d", store.SeatCount("", "A"), store.SeatCount("", "B"))
+			}
+
+			updated := testTicket("deepak@example.com", "B")
+			if err := store.UpdateTicket(updated); err != nil {
+				t.Fatalf("UpdateTicket failed: %v", err)
+			}
+			if ticket, err := store.FindTicket("DEEPAK"); err != nil || ticket.Section != "B" {
+				t.Errorf("Expected updated ticket in section B, got %v (err %v)", ticket, err)
+			}
+
+			moved := testTicket("test@example.com", "A")
+			if err := store.MoveTicket(moved); err != nil {
+				t.Fatalf("MoveTicket failed: %v", err)
+			}
+			if store.SeatCount("", "A") != 0 || store.SeatCount("", "B") != 2 {
+				t.Errorf("Expected 0 seats left in A and 2 in B, got %d and %d", store.SeatCount("", "A"), store.SeatCount("", "B"))
+			}
+
+			if err := store.SwapSeats("DEEPAK", "TEST"); err != nil {
+				t.Fatalf("SwapSeats failed: %v", err)
+			}
+			if ticket, _ := store.FindTicket("TEST"); ticket.Section != "B" {
+				t.Errorf("Expected swapped ticket in section B, got %v", ticket)
+			}
+			if err := store.SwapSeats("DEEPAK", "TEST"); err != nil {
+				t.Fatalf("SwapSeats failed: %v", err)
+			}
+
+			removed, err := store.RemoveTicket("TEST")
+			if err != nil {
+				t.Fatalf("RemoveTicket failed: %v", err)
+			}
+			if removed.User.Email != "test@example.com" {
+				t.Errorf("Removed the wrong ticket: %v", removed)
+			}
+			if store.SeatCount("", "A") != 1 {
+				t.Errorf("Expected the seat to return to section A, got %d", store.SeatCount("", "A"))
+			}
+			if len(store.Tickets()) != 1 {
+				t.Errorf("Expected 1 ticket left, got %d", len(store.Tickets()))
+			}
+
+			if _, err := store.RemoveTicket("TEST"); !errors.Is(err, errTicketNotFound) {
+				t.Errorf("Expected errTicketNotFound, got %v", err)
+			}
+			if err := store.UpdateTicket(testTicket("nobody@example.com", "A")); !errors.Is(err, errTicketNotFound) {
+				t.Errorf("Expected errTicketNotFound, got %v", err)
+			}
+			if err := store.MoveTicket(testTicket("nobody@example.com", "A")); !errors.Is(err, errTicketNotFound) {
+				t.Errorf("Expected errTicketNotFound, got %v", err)
+			}
+
+			replacement := testTicket("new@example.com", "A")
+			if err := store.ReplaceTicket("DEEPAK", replacement); err != nil {
+				t.Fatalf("ReplaceTicket failed: %v", err)
+			}
+			if _, err := store.FindTicket("DEEPAK"); !errors.Is(err, errTicketNotFound) {
+				t.Errorf("Expected the replaced ticket to be gone, got %v", err)
+			}
+			if store.SeatCount("", "A") != 0 || store.SeatCount("", "B") != 3 {
+				t.Errorf("Expected 0 seats left in A and 3 in B, got %d and %d", store.SeatCount("", "A"), store.SeatCount("", "B"))
+			}
+			if err := store.ReplaceTicket("DEEPAK", replacement); !errors.Is(err, errTicketNotFound) {
+				t.Errorf("Expected errTicketNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	if err := store.AddTicket(testTicket("deepak@example.com", "A")); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+	if err := store.AddTicket(testTicket("test@example.com", "B")); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+	if _, err := store.RemoveTicket("TEST"); err != nil {
+		t.Fatalf("RemoveTicket failed: %v", err)
+	}
+	moved := testTicket("deepak@example.com", "B")
+	if err := store.MoveTicket(moved); err != nil {
+		t.Fatalf("MoveTicket failed: %v", err)
+	}
+	replacement := testTicket("new@example.com", "A")
+	if err := store.ReplaceTicket("DEEPAK", replacement); err != nil {
+		t.Fatalf("ReplaceTicket failed: %v", err)
+	}
+
+	reopened, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	assertBookings(t, reopened, []*trainService.Ticket{replacement}, map[string]int{"A": 19, "B": 20})
+	if _, err := reopened.FindDeparture(""); err != nil {
+		t.Errorf("Expected the departure to be replayed from the log: %v", err)
+	}
+}
+
+func TestFileStoreTornRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	ticket := testTicket("deepak@example.com", "A")
+	if err := store.AddTicket(ticket); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+
+	// Simulate a crash half way through logging a second purchase.
+	line, err := encodeRecord(walRecord{Seq: 2, Op: opAddTicket, Ticket: []byte(`{"section":"B"}`)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wal.Write(line[:len(line)/2])
+	wal.Close()
+
+	reopened, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	assertBookings(t, reopened, []*trainService.Ticket{ticket}, map[string]int{"A": 19, "B": 20})
+
+	// The torn record is dropped, so new records are readable after it.
+	second := testTicket("test@example.com", "B")
+	if err := reopened.AddTicket(second); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+	reopened, err = openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	assertBookings(t, reopened, []*trainService.Ticket{ticket, second}, map[string]int{"A": 19, "B": 19})
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	store.snapshotEvery = 3
+
+	// The departure is the first record, so the third ticket triggers the
+	// snapshot.
+	var tickets []*trainService.Ticket
+	for i := 0; i < 3; i++ {
+		ticket := testTicket(fmt.Sprintf("user%d@example.com", i), "A")
+		if err := store.AddTicket(ticket); err != nil {
+			t.Fatalf("AddTicket failed: %v", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); err != nil {
+		t.Fatalf("Expected a snapshot after 3 records: %v", err)
+	}
+	if store.pending != 1 {
+		t.Errorf("Expected 1 record in the log after compaction, got %d", store.pending)
+	}
+
+	reopened, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	assertBookings(t, reopened, tickets, map[string]int{"A": 17, "B": 20})
+	if departure, err := reopened.FindDeparture(""); err != nil || len(departure.Sections) != 2 {
+		t.Errorf("Expected the departure to survive the snapshot, got %v (err %v)", departure, err)
+	}
+}
+
+func TestFileStoreFailedCompaction(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	store.snapshotEvery = 2
+
+	// A directory in place of the log stops compaction from replacing it. The
+	// store still has the old log open.
+	walPath := filepath.Join(dir, walFileName)
+	if err := os.Remove(walPath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.Mkdir(walPath, 0o755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var tickets []*trainService.Ticket
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			if err := os.Remove(walPath); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		ticket := testTicket(fmt.Sprintf("user%d@example.com", i), "A")
+		if err := store.AddTicket(ticket); err != nil {
+			t.Fatalf("Expected records to be kept after a failed compaction, got %v", err)
+		}
+		tickets = append(tickets, ticket)
+	}
+	if store.pending != 0 {
+		t.Errorf("Expected compaction to be retried once it can succeed, got %d pending records", store.pending)
+	}
+	if info, err := os.Stat(walPath); err != nil || info.IsDir() {
+		t.Fatalf("Expected a new log after compaction, got %v (err %v)", info, err)
+	}
+
+	reopened, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	assertBookings(t, reopened, tickets, map[string]int{"A": 17, "B": 20})
+}
+
+func TestFileStoreSnapshotWithStaleLog(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	ticket := testTicket("deepak@example.com", "A")
+	if err := store.AddTicket(ticket); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+	wal, err := os.ReadFile(filepath.Join(dir, walFileName))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Simulate a crash after the snapshot was written but before the log was
+	// truncated: records already in the snapshot must not be applied twice.
+	if err := os.WriteFile(filepath.Join(dir, walFileName), wal, 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reopened, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	assertBookings(t, reopened, []*trainService.Ticket{ticket}, map[string]int{"A": 19, "B": 20})
+}
+
+func TestFileStoreSegments(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := openFileStore(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.AddStation(&trainService.Station{Code: "LON", Name: "London"}); err != nil {
+		t.Fatalf("AddStation failed: %v", err)
+	}
+	if err := store.AddRoute(&trainService.Route{Id: "LON-PAR", Stops: []string{"LON", "LIL", "PAR"}}); err != nil {
+		t.Fatalf("AddRoute failed: %v", err)
+	}
+	if err := store.AddPromoCode(&trainService.PromoCode{Code: "SPRING20", PercentOff: 20, RouteIds: []string{"LON-PAR"}}); err != nil {
+		t.Fatalf("AddPromoCode failed: %v", err)
+	}
+	departure := testDeparture("IC101", map[string]seatLayout{"A": {Rows: 1, SeatsPerRow: 2}})
+	departure.Stops = []string{"LON", "LIL", "PAR"}
+	if err := store.AddDeparture(departure); err != nil {
+		t.Fatalf("AddDeparture failed: %v", err)
+	}
+	ticket := testTicket("deepak@example.com", "A")
+	ticket.DepartureId, ticket.From, ticket.To = "IC101", "LIL", "PAR"
+	if err := store.AddTicket(ticket); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+	invalid := testTicket("test@example.com", "A")
+	invalid.DepartureId, invalid.From, invalid.To = "IC101", "PAR", "LON"
+	if err := store.AddTicket(invalid); !errors.Is(err, errInvalidLeg) {
+		t.Errorf("Expected errInvalidLeg, got %v", err)
+	}
+
+	// Reopen once from the log and once from the snapshot written by Close.
+	for i := 0; i < 2; i++ {
+		reopened, err := openFileStore(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error reopening store: %v", err)
+		}
+		if len(reopened.Stations()) != 1 || len(reopened.Routes()) != 1 {
+			t.Errorf("Expected 1 station and 1 route, got %v and %v", reopened.Stations(), reopened.Routes())
+		}
+		if promo, err := reopened.FindPromoCode("SPRING20"); err != nil || promo.PercentOff != 20 {
+			t.Errorf("Expected promo code SPRING20 to survive, got %v, %v", promo, err)
+		}
+		if counts := reopened.SegmentSeatCounts("IC101", "A"); len(counts) != 2 || counts[0] != 2 || counts[1] != 1 {
+			t.Errorf("Expected 2 seats on LON-LIL and 1 on LIL-PAR, got %v", counts)
+		}
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); err != nil {
+		t.Errorf("Expected a snapshot after Close: %v", err)
+	}
+}
+
+func TestFileStoreHolds(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	for _, token := range []string{"kept", "confirmed", "released"} {
+		hold := &trainService.SeatHold{Token: token, Ticket: testTicket(token+"@example.com", "A"), ExpiresAt: "2024-02-01T12:10:00Z"}
+		hold.Ticket.BookingReference = ""
+		if err := store.AddHold(hold); err != nil {
+			t.Fatalf("AddHold failed: %v", err)
+		}
+	}
+	ticket := testTicket("confirmed@example.com", "A")
+	if err := store.ConfirmHold("confirmed", "", ticket); err != nil {
+		t.Fatalf("ConfirmHold failed: %v", err)
+	}
+	if _, err := store.RemoveHold("released"); err != nil {
+		t.Fatalf("RemoveHold failed: %v", err)
+	}
+	if err := store.ConfirmHold("released", "", testTicket("released@example.com", "A")); !errors.Is(err, errHoldNotFound) {
+		t.Errorf("Expected errHoldNotFound, got %v", err)
+	}
+
+	// Reopen once from the log and once from the snapshot written by Close.
+	for i := 0; i < 2; i++ {
+		reopened, err := openFileStore(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error reopening store: %v", err)
+		}
+		if holds := reopened.Holds(); len(holds) != 1 || holds[0].Token != "kept" {
+			t.Errorf("Expected only hold kept to survive, got %v", holds)
+		}
+		assertBookings(t, reopened, []*trainService.Ticket{ticket}, map[string]int{"A": testLayout["A"].capacity() - 2})
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+}
+
+func TestFileStoreGroupHolds(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	var holds []*trainService.SeatHold
+	var tickets []*trainService.Ticket
+	for _, token := range []string{"first", "second"} {
+		hold := &trainService.SeatHold{Token: token, Ticket: testTicket(token+"@example.com", "A"), ExpiresAt: "2024-02-01T12:10:00Z"}
+		hold.Ticket.BookingReference = ""
+		holds = append(holds, hold)
+		tickets = append(tickets, testTicket(token+"@example.com", "A"))
+	}
+	if err := store.AddHolds(holds); err != nil {
+		t.Fatalf("AddHolds failed: %v", err)
+	}
+	// A batch with a missing hold confirms none of them.
+	if err := store.ConfirmHolds([]string{"first", "missing"}, tickets); !errors.Is(err, errHoldNotFound) {
+		t.Errorf("Expected errHoldNotFound, got %v", err)
+	}
+	if len(store.Tickets()) != 0 {
+		t.Errorf("Expected no ticket booked by a failed batch, got %v", store.Tickets())
+	}
+	if err := store.ConfirmHolds([]string{"first", "second"}, tickets); err != nil {
+		t.Fatalf("ConfirmHolds failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		reopened, err := openFileStore(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error reopening store: %v", err)
+		}
+		if holds := reopened.Holds(); len(holds) != 0 {
+			t.Errorf("Expected every hold confirmed, got %v", holds)
+		}
+		assertBookings(t, reopened, tickets, map[string]int{"A": testLayout["A"].capacity() - 2})
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+}
+
+func TestFileStoreSettleDeparture(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	if err := store.AddDeparture(testDeparture("later", testLayout)); err != nil {
+		t.Fatalf("AddDeparture failed: %v", err)
+	}
+	for _, email := range []string{"seated@example.com", "bumped@example.com", "cancelled@example.com"} {
+		if err := store.AddTicket(testTicket(email, "A")); err != nil {
+			t.Fatalf("AddTicket failed: %v", err)
+		}
+	}
+	closed := testDeparture("", testLayout)
+	closed.CheckInClosed = true
+	seated := testTicket("seated@example.com", "A")
+	seated.CheckedIn = true
+	bumped := testTicket("bumped@example.com", "B")
+	bumped.DepartureId = "later"
+	// A batch with a missing ticket settles nothing.
+	if err := store.SettleDeparture(closed, []*trainService.Ticket{seated, bumped}, []string{"MISSING"}); !errors.Is(err, errTicketNotFound) {
+		t.Errorf("Expected errTicketNotFound, got %v", err)
+	}
+	if departure, _ := store.FindDeparture(""); departure.CheckInClosed {
+		t.Errorf("Expected the departure left open by a failed batch")
+	}
+	if err := store.SettleDeparture(closed, []*trainService.Ticket{seated, bumped}, []string{"CANCELLED"}); err != nil {
+		t.Fatalf("SettleDeparture failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		reopened, err := openFileStore(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error reopening store: %v", err)
+		}
+		if departure, _ := reopened.FindDeparture(""); !departure.CheckInClosed {
+			t.Errorf("Expected check-in closed, got %v", departure)
+		}
+		assertBookings(t, reopened, []*trainService.Ticket{seated, bumped}, map[string]int{"A": testLayout["A"].capacity() - 1})
+		if free := reopened.SeatCount("later", "B"); free != testLayout["B"].capacity()-1 {
+			t.Errorf("Expected the bumped ticket to take a seat on the later departure, got %d free", free)
+		}
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+}
+
+func TestFileStoreWaitlist(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	for _, id := range []string{"kept", "left"} {
+		entry := &trainService.WaitlistEntry{Id: id, Ticket: testTicket(id+"@example.com", "A"), JoinedAt: "2024-02-01T12:00:00Z"}
+		if err := store.AddWaitlistEntry(entry); err != nil {
+			t.Fatalf("AddWaitlistEntry failed: %v", err)
+		}
+	}
+	offered := &trainService.WaitlistEntry{Id: "kept", Ticket: testTicket("kept@example.com", "A"), Offer: &trainService.SeatHold{Token: "offer"}}
+	if err := store.UpdateWaitlistEntry(offered); err != nil {
+		t.Fatalf("UpdateWaitlistEntry failed: %v", err)
+	}
+	if _, err := store.RemoveWaitlistEntry("left"); err != nil {
+		t.Fatalf("RemoveWaitlistEntry failed: %v", err)
+	}
+	if _, err := store.RemoveWaitlistEntry("left"); !errors.Is(err, errWaitlistNotFound) {
+		t.Errorf("Expected errWaitlistNotFound, got %v", err)
+	}
+	if err := store.AddTicket(testTicket("deepak@example.com", "A")); err != nil {
+		t.Fatalf("AddTicket failed: %v", err)
+	}
+	// Section A grows by a row of four seats.
+	if err := store.UpdateDeparture(testDeparture("", map[string]seatLayout{"A": {Rows: 6, SeatsPerRow: 4}, "B": testLayout["B"]})); err != nil {
+		t.Fatalf("UpdateDeparture failed: %v", err)
+	}
+	if err := store.UpdateDeparture(testDeparture("missing", testLayout)); !errors.Is(err, errDepartureNotFound) {
+		t.Errorf("Expected errDepartureNotFound, got %v", err)
+	}
+
+	// Reopen once from the log and once from the snapshot written by Close.
+	for i := 0; i < 2; i++ {
+		reopened, err := openFileStore(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error reopening store: %v", err)
+		}
+		if waitlist := reopened.Waitlist(); len(waitlist) != 1 || !proto.Equal(waitlist[0], offered) {
+			t.Errorf("Expected only the offered entry to survive, got %v", waitlist)
+		}
+		if reopened.SeatCount("", "A") != 23 || reopened.SeatCount("", "B") != 20 {
+			t.Errorf("Expected 23 seats left in A and 20 in B, got %d and %d", reopened.SeatCount("", "A"), reopened.SeatCount("", "B"))
+		}
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+}
+
+func TestFileStorePendingUpgrades(t *testing.T) {
+	dir := t.TempDir()
+
+	store := openTestFileStore(t, dir)
+	var kept *trainService.PendingUpgrade
+	for _, key := range []string{"kept", "settled"} {
+		upgrade := &trainService.PendingUpgrade{ChargeKey: key, Original: testTicket(key+"@example.com", "A"), Difference: eur(500)}
+		if err := store.AddPendingUpgrade(upgrade); err != nil {
+			t.Fatalf("AddPendingUpgrade failed: %v", err)
+		}
+		if key == "kept" {
+			kept = upgrade
+		}
+	}
+	if err := store.RemovePendingUpgrade("settled"); err != nil {
+		t.Fatalf("RemovePendingUpgrade failed: %v", err)
+	}
+	if err := store.RemovePendingUpgrade("settled"); !errors.Is(err, errUpgradeNotFound) {
+		t.Errorf("Expected errUpgradeNotFound, got %v", err)
+	}
+
+	// Reopen once from the log and once from the snapshot written by Close.
+	for i := 0; i < 2; i++ {
+		reopened, err := openFileStore(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error reopening store: %v", err)
+		}
+		if upgrades := reopened.PendingUpgrades(); len(upgrades) != 1 || !proto.Equal(upgrades[0], kept) {
+			t.Errorf("Expected only the kept upgrade to survive, got %v", upgrades)
+		}
+		if err := reopened.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+}
+
+func assertBookings(t *testing.T, store BookingStore, tickets []*trainService.Ticket, seatCount map[string]int) {
+	t.Helper()
+
+	got := store.Tickets()
+	if len(got) != len(tickets) {
+		t.Fatalf("Expected %d tickets, got %d", len(tickets), len(got))
+	}
+	for i := range tickets {
+		if !proto.Equal(tickets[i], got[i]) {
+			t.Errorf("Expected ticket %v, got %v", tickets[i], got[i])
+		}
+	}
+	for section, count := range seatCount {
+		if store.SeatCount("", section) != count {
+			t.Errorf("Expected %d seats in section %s, got %d", count, section, store.SeatCount("", section))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// consentToken signs a passenger's agreement to swap their current seat with
+// other. The token covers the passenger's ticket, section and seat, so it
+// stops working once either changes and cannot be replayed after the swap.
+func (s *TrainServer) consentToken(ticket *trainService.Ticket, other string) string {
+	mac := hmac.New(sha256.New, s.consentKey)
+	fmt.Fprintf(mac, "%s\x00%s\x00%s\x00%s", ticket.User.Email, other, ticket.BookingReference, ticket.Section)
+	if ticket.Seat != nil {
+		fmt.Fprintf(mac, "\x00%d\x00%d", ticket.Seat.Row, ticket.Seat.Number)
+	}
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *TrainServer) GrantSwapConsent(ctx context.Context, req *trainService.SwapConsentRequest) (*trainService.SwapConsent, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("(User, Other, BookingReference) fields are empty",
+		field{"user.email", req.User.GetEmail()}, field{"other.email", req.Other.GetEmail()},
+		field{"booking_reference", req.BookingReference}); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Only the passenger knows the booking reference, so the email alone does
+	// not get a token.
+	ticket, err := s.passengerTicket(req.User.Email, req.BookingReference)
+	if err != nil {
+		return nil, err
+	}
+	return &trainService.SwapConsent{Token: s.consentToken(ticket, req.Other.Email)}, nil
+}
+
+// passengerTicket returns the ticket booked under reference for email. A
+// ticket of somebody else is not told apart from a missing one. Callers must
+// hold s.mu.
+func (s *TrainServer) passengerTicket(email, reference string) (*trainService.Ticket, error) {
+	ticket, err := s.store.FindTicket(reference)
+	if err != nil || ticket.User.Email != email {
+		return nil, bookingNotFound(reference)
+	}
+	return ticket, nil
+}
+
+func (s *TrainServer) SwapSeats(ctx context.Context, req *trainService.SwapSeatsRequest) (*trainService.SwapSeatsResponse, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("(First, Second) emails are empty",
+		field{"first.email", req.First.GetEmail()}, field{"second.email", req.Second.GetEmail()}); err != nil {
+		return nil, err
+	}
+	if err := requireFields("(FirstConsent, SecondConsent) fields are empty",
+		field{"first_consent", req.FirstConsent}, field{"second_consent", req.SecondConsent}); err != nil {
+		return nil, err
+	}
+	if err := requireFields("(FirstBookingReference, SecondBookingReference) fields are empty",
+		field{"first_booking_reference", req.FirstBookingReference},
+		field{"second_booking_reference", req.SecondBookingReference}); err != nil {
+		return nil, err
+	}
+	if req.First.Email == req.Second.Email {
+		return nil, invalidField("second.email", "cannot swap a seat with itself")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first, err := s.passengerTicket(req.First.Email, req.FirstBookingReference)
+	if err != nil {
+		return nil, err
+	}
+	second, err := s.passengerTicket(req.Second.Email, req.SecondBookingReference)
+	if err != nil {
+		return nil, err
+	}
+	if first.DepartureId != second.DepartureId {
+		return nil, preconditionFailed("DEPARTURE_MISMATCH", "departure:"+second.DepartureId,
+			fmt.Sprintf("%s and %s are booked on different departures", first.User.Email, second.User.Email))
+	}
+	// Seats are only free for the leg they were sold for.
+	if first.From != second.From || first.To != second.To {
+		return nil, preconditionFailed("LEG_MISMATCH", fmt.Sprintf("leg:%s-%s", second.From, second.To),
+			fmt.Sprintf("%s and %s travel between different stations", first.User.Email, second.User.Email))
+	}
+	departure, err := s.store.FindDeparture(first.DepartureId)
+	if err != nil {
+		return nil, internalError("failed to load departure", err)
+	}
+	if err := checkInOpen(departure); err != nil {
+		return nil, err
+	}
+	// Passengers keep the fare they paid, so each must move to a section that
+	// sells their fare class.
+	if err := s.checkClassSold(departure, first, second.Section); err != nil {
+		return nil, err
+	}
+	if err := s.checkClassSold(departure, second, first.Section); err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(req.FirstConsent), []byte(s.consentToken(first, second.User.Email))) {
+		return nil, status.Errorf(codes.PermissionDenied, "invalid swap consent from %s", first.User.Email)
+	}
+	if !hmac.Equal([]byte(req.SecondConsent), []byte(s.consentToken(second, first.User.Email))) {
+		return nil, status.Errorf(codes.PermissionDenied, "invalid swap consent from %s", second.User.Email)
+	}
+
+	if err := s.store.SwapSeats(first.BookingReference, second.BookingReference); err != nil {
+		return nil, internalError("failed to swap seats", err)
+	}
+	first, _ = s.store.FindTicket(first.BookingReference)
+	second, _ = s.store.FindTicket(second.BookingReference)
+	return &trainService.SwapSeatsResponse{First: first, Second: second}, nil
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSwapSeats(t *testing.T) {
+	store := newTestStore(testLayout, map[string]int{"A": 19, "B": 19})
+	server := &TrainServer{store: store, consentKey: []byte("test key")}
+	ctx := context.Background()
+
+	deepak := testTicket("deepak@example.com", "A")
+	deepak.Seat = &trainService.Seat{Row: 1, Number: 1}
+	test := testTicket("test@example.com", "B")
+	test.Seat = &trainService.Seat{Row: 2, Number: 3}
+	store.tickets = []*trainService.Ticket{deepak, test}
+
+	consent := func(ticket *trainService.Ticket, other string) string {
+		resp, err := server.GrantSwapConsent(ctx, &trainService.SwapConsentRequest{
+			User:             &trainService.User{Email: ticket.User.Email},
+			Other:            &trainService.User{Email: other},
+			BookingReference: ticket.BookingReference,
+		})
+		if err != nil {
+			t.Fatalf("GrantSwapConsent failed: %v", err)
+		}
+		return resp.Token
+	}
+	deepakConsent := consent(deepak, "test@example.com")
+	testConsent := consent(test, "deepak@example.com")
+
+	tests := []struct {
+		name        string
+		request     *trainService.SwapSeatsRequest
+		expectedErr bool
+	}{
+		{
+			name:        "Invoke SwapSeats func with nil request",
+			request:     nil,
+			expectedErr: true,
+		},
+		{
+			name: "Missing second passenger",
+			request: &trainService.SwapSeatsRequest{
+				First:        &trainService.User{Email: "deepak@example.com"},
+				FirstConsent: deepakConsent,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Second passenger has no ticket",
+			request: &trainService.SwapSeatsRequest{
+				First:                  &trainService.User{Email: "deepak@example.com"},
+				FirstConsent:           deepakConsent,
+				Second:                 &trainService.User{Email: "nobody@example.com"},
+				SecondConsent:          testConsent,
+				FirstBookingReference:  deepak.BookingReference,
+				SecondBookingReference: test.BookingReference,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Consent given for somebody else",
+			request: &trainService.SwapSeatsRequest{
+				First:                  &trainService.User{Email: "deepak@example.com"},
+				FirstConsent:           consent(deepak, "nobody@example.com"),
+				Second:                 &trainService.User{Email: "test@example.com"},
+				SecondConsent:          testConsent,
+				FirstBookingReference:  deepak.BookingReference,
+				SecondBookingReference: test.BookingReference,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Tokens swapped between passengers",
+			request: &trainService.SwapSeatsRequest{
+				First:                  &trainService.User{Email: "deepak@example.com"},
+				FirstConsent:           testConsent,
+				Second:                 &trainService.User{Email: "test@example.com"},
+				SecondConsent:          deepakConsent,
+				FirstBookingReference:  deepak.BookingReference,
+				SecondBookingReference: test.BookingReference,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "Both passengers consent",
+			request: &trainService.SwapSeatsRequest{
+				First:                  &trainService.User{Email: "deepak@example.com"},
+				FirstConsent:           deepakConsent,
+				Second:                 &trainService.User{Email: "test@example.com"},
+				SecondConsent:          testConsent,
+				FirstBookingReference:  deepak.BookingReference,
+				SecondBookingReference: test.BookingReference,
+			},
+			expectedErr: false,
+		},
+		{
+			name: "Consent cannot be reused after the swap",
+			request: &trainService.SwapSeatsRequest{
+				First:                  &trainService.User{Email: "deepak@example.com"},
+				FirstConsent:           deepakConsent,
+				Second:                 &trainService.User{Email: "test@example.com"},
+				SecondConsent:          testConsent,
+				FirstBookingReference:  deepak.BookingReference,
+				SecondBookingReference: test.BookingReference,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := server.SwapSeats(ctx, tc.request)
+
+			if tc.expectedErr && err == nil {
+				t.Error("Expected an error, got nil")
+			}
+			if !tc.expectedErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tc.expectedErr {
+				return
+			}
+			if resp.First.Section != "B" || !proto.Equal(resp.First.Seat, test.Seat) {
+				t.Errorf("Expected deepak in B %v, got %v", test.Seat, resp.First)
+			}
+			if resp.Second.Section != "A" || !proto.Equal(resp.Second.Seat, deepak.Seat) {
+				t.Errorf("Expected test in A %v, got %v", deepak.Seat, resp.Second)
+			}
+		})
+	}
+
+	if store.SeatCount("", "A") != 19 || store.SeatCount("", "B") != 19 {
+		t.Errorf("Swapping changed seat counts: A %d, B %d", store.SeatCount("", "A"), store.SeatCount("", "B"))
+	}
+}
+
+func TestSwapSeatsAcrossFareClasses(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts, consentKey: []byte("test key"), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	purchase := func(email, section string) *trainService.Ticket {
+		ticket := testJourney(email, departure.Id, section)
+		booked, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		return booked
+	}
+	consent := func(ticket *trainService.Ticket, other string) string {
+		resp, err := server.GrantSwapConsent(ctx, &trainService.SwapConsentRequest{
+			User:             &trainService.User{Email: ticket.User.Email},
+			Other:            &trainService.User{Email: other},
+			BookingReference: ticket.BookingReference,
+		})
+		if err != nil {
+			t.Fatalf("GrantSwapConsent failed: %v", err)
+		}
+		return resp.Token
+	}
+
+	standard := purchase("deepak@example.com", "S")
+	first := purchase("test@example.com", "F")
+	_, err := server.SwapSeats(ctx, &trainService.SwapSeatsRequest{
+		First:                  &trainService.User{Email: "deepak@example.com"},
+		FirstConsent:           consent(standard, "test@example.com"),
+		Second:                 &trainService.User{Email: "test@example.com"},
+		SecondConsent:          consent(first, "deepak@example.com"),
+		FirstBookingReference:  standard.BookingReference,
+		SecondBookingReference: first.BookingReference,
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition for a standard ticket moving to first class, got %v", err)
+	}
+	for _, ticket := range []*trainService.Ticket{standard, first} {
+		if stored, _ := server.store.FindTicket(ticket.BookingReference); !proto.Equal(stored, ticket) {
+			t.Errorf("Expected %s unchanged, got %v", ticket.BookingReference, stored)
+		}
+	}
+}
+
+func TestGrantSwapConsent(t *testing.T) {
+	store := newTestStore(testLayout, map[string]int{"A": 19, "B": 19})
+	server := &TrainServer{store: store, consentKey: []byte("test key")}
+	ctx := context.Background()
+
+	deepak := testTicket("deepak@example.com", "A")
+	deepak.Seat = &trainService.Seat{Row: 1, Number: 1}
+	test := testTicket("test@example.com", "B")
+	test.Seat = &trainService.Seat{Row: 2, Number: 3}
+	store.tickets = []*trainService.Ticket{deepak, test}
+
+	tests := []struct {
+		name         string
+		request      *trainService.SwapConsentRequest
+		expectedCode codes.Code
+	}{
+		{
+			name:         "Invoke GrantSwapConsent func with nil request",
+			request:      nil,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Email alone",
+			request: &trainService.SwapConsentRequest{
+				User:  &trainService.User{Email: "deepak@example.com"},
+				Other: &trainService.User{Email: "test@example.com"},
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Booking reference of another passenger",
+			request: &trainService.SwapConsentRequest{
+				User:             &trainService.User{Email: "deepak@example.com"},
+				Other:            &trainService.User{Email: "test@example.com"},
+				BookingReference: test.BookingReference,
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Own booking reference",
+			request: &trainService.SwapConsentRequest{
+				User:             &trainService.User{Email: "deepak@example.com"},
+				Other:            &trainService.User{Email: "test@example.com"},
+				BookingReference: deepak.BookingReference,
+			},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := server.GrantSwapConsent(ctx, tc.request)
+			if status.Code(err) != tc.expectedCode {
+				t.Fatalf("Expected %v, got %v", tc.expectedCode, err)
+			}
+			if err == nil && resp.Token == "" {
+				t.Error("Expected a consent token")
+			}
+		})
+	}
+}
+
+func TestSwapSeatsAfterCheckIn(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), consentKey: []byte("test key"), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	var consents, references []string
+	for _, passenger := range [][2]string{{"deepak@example.com", "test@example.com"}, {"test@example.com", "deepak@example.com"}} {
+		ticket := testJourney(passenger[0], departure.Id, "B")
+		booked, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		if _, err := server.CheckIn(ctx, &trainService.BookingReference{Reference: booked.BookingReference}); err != nil {
+			t.Fatalf("CheckIn failed: %v", err)
+		}
+		consent, err := server.GrantSwapConsent(ctx, &trainService.SwapConsentRequest{
+			User:             booked.User,
+			Other:            &trainService.User{Email: passenger[1]},
+			BookingReference: booked.BookingReference,
+		})
+		if err != nil {
+			t.Fatalf("GrantSwapConsent failed: %v", err)
+		}
+		consents = append(consents, consent.Token)
+		references = append(references, booked.BookingReference)
+	}
+	if _, err := server.CloseCheckIn(ctx, &trainService.CloseCheckInRequest{DepartureId: departure.Id}); err != nil {
+		t.Fatalf("CloseCheckIn failed: %v", err)
+	}
+
+	_, err := server.SwapSeats(ctx, &trainService.SwapSeatsRequest{
+		First:                  &trainService.User{Email: "deepak@example.com"},
+		FirstConsent:           consents[0],
+		Second:                 &trainService.User{Email: "test@example.com"},
+		SecondConsent:          consents[1],
+		FirstBookingReference:  references[0],
+		SecondBookingReference: references[1],
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition once check-in has closed, got %v", err)
+	}
+}
+
+func TestSwapSeatsOnLaterBooking(t *testing.T) {
+	server := &TrainServer{store: newRouteStore(), consentKey: []byte("test key"), duplicates: duplicateReject}
+	ctx := context.Background()
+
+	var departures []string
+	for _, date := range []string{"2024-03-04", "2024-03-05"} {
+		departure := createDeparture(t, server, testSchedule("IC101", date, "09:30"))
+		departures = append(departures, departure.Id)
+	}
+	purchase := func(email, departure, section string) *trainService.Ticket {
+		ticket := testJourney(email, departure, section)
+		booked, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		return booked
+	}
+	consent := func(ticket *trainService.Ticket, other string) string {
+		resp, err := server.GrantSwapConsent(ctx, &trainService.SwapConsentRequest{
+			User:             ticket.User,
+			Other:            &trainService.User{Email: other},
+			BookingReference: ticket.BookingReference,
+		})
+		if err != nil {
+			t.Fatalf("GrantSwapConsent failed: %v", err)
+		}
+		return resp.Token
+	}
+
+	// Deepak's first ticket is on the earlier departure.
+	purchase("deepak@example.com", departures[0], "A")
+	deepak := purchase("deepak@example.com", departures[1], "A")
+	test := purchase("test@example.com", departures[1], "B")
+	resp, err := server.SwapSeats(ctx, &trainService.SwapSeatsRequest{
+		First:                  deepak.User,
+		FirstConsent:           consent(deepak, "test@example.com"),
+		Second:                 test.User,
+		SecondConsent:          consent(test, "deepak@example.com"),
+		FirstBookingReference:  deepak.BookingReference,
+		SecondBookingReference: test.BookingReference,
+	})
+	if err != nil {
+		t.Fatalf("SwapSeats failed: %v", err)
+	}
+	if resp.First.BookingReference != deepak.BookingReference || resp.First.Section != "B" || resp.Second.Section != "A" {
+		t.Errorf("Expected %s to move to B and %s to A, got %v", deepak.BookingReference, test.BookingReference, resp)
+	}
+}
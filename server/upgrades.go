@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UpgradeTicket moves a ticket to a fare class of higher rank and charges the
+// fare difference. A new seat in a section selling the class is held while
+// the difference is charged, and the ticket is only moved to it once the
+// charge succeeds, in a single store change that also frees the old seat.
+// Upgrades to a fare class sold in the ticket's own section keep the seat.
+func (s *TrainServer) UpgradeTicket(ctx context.Context, req *trainService.UpgradeTicketRequest) (*trainService.Ticket, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("(BookingReference, FareClass) fields are empty",
+		field{"booking_reference", req.BookingReference}, field{"fare_class", req.FareClass}); err != nil {
+		return nil, err
+	}
+
+	upgrade, err := s.prepareUpgrade(req)
+	if err != nil {
+		return nil, err
+	}
+	var payment string
+	if upgrade.ChargeKey != "" {
+		var chargeErr error
+		if payment, chargeErr = s.charge(ctx, upgrade.ChargeKey, upgrade.Original.User.Email, upgrade.Difference); chargeErr != nil {
+			err = paymentFailed(chargeErr)
+		}
+	}
+	return s.settleUpgrade(ctx, upgrade, payment, err)
+}
+
+// settleUpgrade makes upgrade once its difference has been paid by payment, or
+// handles err, the reason it could not be paid. An upgrade that is not made
+// releases its held seat and refunds the payment. Callers must not hold s.mu.
+func (s *TrainServer) settleUpgrade(ctx context.Context, upgrade *trainService.PendingUpgrade, payment string, err error) (*trainService.Ticket, error) {
+	s.mu.Lock()
+	var ticket *trainService.Ticket
+	if err == nil {
+		ticket, err = s.bookUpgrade(upgrade, payment)
+	}
+	// A payment is only refunded once the upgrade is off record, so it is not
+	// settled again later.
+	settled := true
+	if upgrade.ChargeKey != "" {
+		delete(s.paying, upgrade.ChargeKey)
+		if removeErr := s.store.RemovePendingUpgrade(upgrade.ChargeKey); removeErr != nil && !errors.Is(removeErr, errUpgradeNotFound) {
+			log.Printf("failed to remove pending upgrade of %s: %v", upgrade.Original.BookingReference, removeErr)
+			settled = false
+		}
+	}
+	if upgrade.HoldToken != "" {
+		delete(s.paying, upgrade.HoldToken)
+		if err != nil {
+			if _, releaseErr := s.store.RemoveHold(upgrade.HoldToken); releaseErr != nil && !errors.Is(releaseErr, errHoldNotFound) {
+				log.Printf("failed to release seat hold after a failed upgrade: %v", releaseErr)
+			}
+		}
+		// Either the old seat or the held one is free again.
+		s.offerWaitlistSeats(upgrade.Original.DepartureId)
+	}
+	s.mu.Unlock()
+
+	if payment != "" && err != nil && settled {
+		if refundErr := s.paymentProvider().Refund(context.WithoutCancel(ctx), payment, upgrade.Difference); refundErr != nil {
+			log.Printf("failed to refund payment %s for an upgrade that was not made: %v", payment, refundErr)
+		}
+	}
+	return ticket, err
+}
+
+// prepareUpgrade prices the upgrade of a ticket and holds a seat for it if it
+// changes section. An upgrade with a difference to pay is put on record with
+// the key it is to be charged under.
+func (s *TrainServer) prepareUpgrade(req *trainService.UpgradeTicketRequest) (*trainService.PendingUpgrade, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket, err := s.store.FindTicket(req.BookingReference)
+	if err != nil {
+		return nil, bookingNotFound(req.BookingReference)
+	}
+	departure, err := s.store.FindDeparture(ticket.DepartureId)
+	if err != nil {
+		return nil, internalError("failed to load departure", err)
+	}
+	if err := checkInOpen(departure); err != nil {
+		return nil, err
+	}
+	table := s.fareTable()
+	currentSection, _ := findSection(departure, ticket.Section)
+	currentName, current, err := table.fareClass(ticket.FareClass, currentSection)
+	if err != nil {
+		return nil, internalError("failed to find the fare class of the ticket", err)
+	}
+	travelled := ticketLeg(departure, ticket)
+
+	section, err := s.upgradeSection(departure, ticket, travelled, req)
+	if err != nil {
+		return nil, err
+	}
+	name, class, err := table.fareClass(req.FareClass, section)
+	if err != nil {
+		return nil, err
+	}
+	if class.Rank <= current.Rank {
+		return nil, preconditionFailed("NOT_AN_UPGRADE", "fare_class",
+			fmt.Sprintf("fare class %s does not rank above %s", name, currentName))
+	}
+
+	// A promo code redeemed at purchase keeps applying even if it has expired
+	// since.
+	quote, err := s.quote(departure, travelled, section.Section, name, ticket.PassengerType, ticket.Price.GetCurrencyCode())
+	if err != nil {
+		return nil, err
+	}
+	if promo, err := s.store.FindPromoCode(ticket.PromoCode); err == nil {
+		applyPromo(quote, promo, s.exchangeRates())
+	}
+	upgraded := proto.Clone(ticket).(*trainService.Ticket)
+	upgraded.Section = section.Section
+	upgraded.FareClass = name
+	upgraded.Price = quote.Price
+	upgraded.OriginalFare = quote.OriginalFare
+	upgraded.Discounts = quote.Discounts
+	// The difference is charged on what was paid, not on the ticket's price.
+	paid := ticket.AmountPaid
+	if paid == nil {
+		paid = ticket.Price
+	}
+	upgrade := &trainService.PendingUpgrade{
+		Original: ticket,
+		Upgraded: upgraded,
+		Difference: &trainService.Money{
+			CurrencyCode: quote.Price.CurrencyCode,
+			MinorUnits:   max(quote.Price.MinorUnits-paid.GetMinorUnits(), 0),
+		},
+	}
+	if section.Section != ticket.Section {
+		seat, err := s.allocateSeat(departure, section.Section, travelled, nil, ticket.AccessibleSeat, "")
+		if err != nil {
+			return nil, err
+		}
+		upgraded.Seat = seat
+		// The held copy has no booking reference, so the ticket's own seat is
+		// still taken while the upgrade is paid for.
+		held := proto.Clone(upgraded).(*trainService.Ticket)
+		held.BookingReference = ""
+		hold, err := s.placeHold(held)
+		if err != nil {
+			return nil, err
+		}
+		upgrade.HoldToken = hold.Token
+		s.startPayment(hold.Token)
+	}
+	if upgrade.Difference.MinorUnits == 0 {
+		return upgrade, nil
+	}
+
+	if upgrade.ChargeKey, err = newToken(); err == nil {
+		err = s.store.AddPendingUpgrade(upgrade)
+	}
+	if err != nil {
+		if upgrade.HoldToken != "" {
+			delete(s.paying, upgrade.HoldToken)
+			if _, releaseErr := s.store.RemoveHold(upgrade.HoldToken); releaseErr != nil {
+				log.Printf("failed to release seat hold of an upgrade that could not be recorded: %v", releaseErr)
+			}
+			s.offerWaitlistSeats(ticket.DepartureId)
+		}
+		return nil, internalError("failed to record upgrade", err)
+	}
+	s.startPayment(upgrade.ChargeKey)
+	return upgrade, nil
+}
+
+// upgradeSection returns the section an upgrade of ticket to the fare class of
+// req moves to: the requested one, or else the ticket's own section if it
+// sells the class, or else the first section selling the class with a seat
+// free on the leg travelled. Callers must hold s.mu.
+func (s *TrainServer) upgradeSection(departure *trainService.Departure, ticket *trainService.Ticket, travelled leg, req *trainService.UpgradeTicketRequest) (*trainService.SectionCapacity, error) {
+	table := s.fareTable()
+	if req.Section != "" {
+		section, ok := findSection(departure, req.Section)
+		if !ok {
+			return nil, invalidField("section", fmt.Sprintf("section %s does not exist on departure %s", req.Section, departure.Id))
+		}
+		if req.Section != ticket.Section && s.legSeats(departure, req.Section, travelled, nil) <= 0 {
+			return nil, sectionSoldOut(req.Section)
+		}
+		return section, nil
+	}
+
+	own, _ := findSection(departure, ticket.Section)
+	if _, _, err := table.fareClass(req.FareClass, own); err == nil {
+		return own, nil
+	}
+	var soldOut *trainService.SectionCapacity
+	for _, section := range departure.Sections {
+		if _, _, err := table.fareClass(req.FareClass, section); err != nil {
+			continue
+		}
+		if s.legSeats(departure, section.Section, travelled, nil) > 0 {
+			return section, nil
+		}
+		if soldOut == nil {
+			soldOut = section
+		}
+	}
+	if soldOut != nil {
+		return nil, sectionSoldOut(soldOut.Section)
+	}
+	return nil, invalidField("fare_class", fmt.Sprintf("no section of departure %s sells fare class %s", departure.Id, normaliseFareClass(req.FareClass)))
+}
+
+// bookUpgrade replaces the ticket of upgrade by its upgraded copy, paid for by
+// payment, if the ticket has not changed since the upgrade was priced.
+// Callers must hold s.mu.
+func (s *TrainServer) bookUpgrade(upgrade *trainService.PendingUpgrade, payment string) (*trainService.Ticket, error) {
+	current, err := s.store.FindTicket(upgrade.Original.BookingReference)
+	// The server may have stopped after making the upgrade but before taking
+	// it off record.
+	if err == nil && payment != "" && paidBy(current, payment) {
+		return current, nil
+	}
+	if err != nil || !proto.Equal(current, upgrade.Original) {
+		return nil, status.Error(codes.Aborted, "ticket changed while the upgrade was paid for, no upgrade was made")
+	}
+
+	ticket := proto.Clone(upgrade.Upgraded).(*trainService.Ticket)
+	if payment != "" {
+		ticket.UpgradePayments = append(ticket.UpgradePayments, &trainService.Payment{Reference: payment, Amount: upgrade.Difference})
+		if ticket.AmountPaid != nil {
+			ticket.AmountPaid.MinorUnits += upgrade.Difference.MinorUnits
+		}
+	}
+	if upgrade.HoldToken == "" {
+		err = s.store.UpdateTicket(ticket)
+	} else {
+		err = s.store.ConfirmHold(upgrade.HoldToken, ticket.BookingReference, ticket)
+	}
+	if err != nil {
+		return nil, internalError("failed to save upgraded ticket", err)
+	}
+	return ticket, nil
+}
+
+// paidBy reports whether payment paid for an upgrade of ticket.
+func paidBy(ticket *trainService.Ticket, payment string) bool {
+	for _, upgrade := range ticket.UpgradePayments {
+		if upgrade.Reference == payment {
+			return true
+		}
+	}
+	return false
+}
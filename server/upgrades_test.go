@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUpgradeTicket(t *testing.T) {
+	now := time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts, payments: payments, duplicates: duplicateAllow, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	purchase := func(email, section, class string) *trainService.Ticket {
+		ticket := testJourney(email, departure.Id, section)
+		ticket.FareClass = class
+		booked, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		return booked
+	}
+
+	// An upgrade to first class moves the ticket to the first class coach and
+	// charges the difference.
+	booked := purchase("deepak@example.com", "S", "")
+	if booked.FareClass != "standard" {
+		t.Errorf("Expected the section's own fare class, got %q", booked.FareClass)
+	}
+	upgraded, err := server.UpgradeTicket(ctx, &trainService.UpgradeTicketRequest{BookingReference: booked.BookingReference, FareClass: "First"})
+	if err != nil {
+		t.Fatalf("UpgradeTicket failed: %v", err)
+	}
+	difference := upgraded.Price.MinorUnits - booked.Price.MinorUnits
+	if upgraded.Section != "F" || upgraded.FareClass != "first" || upgraded.BookingReference != booked.BookingReference || difference <= 0 {
+		t.Fatalf("Expected the ticket moved to first class at a higher price, got %v", upgraded)
+	}
+	if len(upgraded.UpgradePayments) != 1 || !proto.Equal(payments.remaining(upgraded.UpgradePayments[0].Reference), eur(difference)) ||
+		!proto.Equal(upgraded.AmountPaid, eur(upgraded.Price.MinorUnits)) {
+		t.Errorf("Expected a charge of the difference %d, got %v", difference, upgraded)
+	}
+	if free := server.store.SeatCount(departure.Id, "S"); free != 4 {
+		t.Errorf("Expected the standard seat freed, got %d free seats", free)
+	}
+	if len(server.store.Holds()) != 0 {
+		t.Errorf("Expected no seat hold left, got %v", server.store.Holds())
+	}
+
+	// Going back down is not an upgrade.
+	if _, err := server.UpgradeTicket(ctx, &trainService.UpgradeTicketRequest{BookingReference: booked.BookingReference, FareClass: "standard"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition, got %v", err)
+	}
+
+	// The cancellation fee is kept from the original payment, the upgrade
+	// charge is paid back first.
+	cancelled, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: booked.BookingReference})
+	if err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	fee := cancelled.Refund.Fee.MinorUnits
+	if !proto.Equal(payments.remaining(upgraded.UpgradePayments[0].Reference), eur(0)) || !proto.Equal(payments.remaining(booked.PaymentReference), eur(fee)) {
+		t.Errorf("Expected the upgrade refunded in full and the fee kept from the original payment, got %v and %v",
+			payments.remaining(upgraded.UpgradePayments[0].Reference), payments.remaining(booked.PaymentReference))
+	}
+
+	// The difference is charged on what was paid, even where the ticket's
+	// price no longer matches it.
+	repriced := purchase("repriced@example.com", "S", "")
+	stale := proto.Clone(repriced).(*trainService.Ticket)
+	stale.Price = eur(repriced.Price.MinorUnits + 1000)
+	if err := server.store.UpdateTicket(stale); err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	upgraded, err = server.UpgradeTicket(ctx, &trainService.UpgradeTicketRequest{BookingReference: repriced.BookingReference, FareClass: "first"})
+	if err != nil {
+		t.Fatalf("UpgradeTicket failed: %v", err)
+	}
+	difference = upgraded.Price.MinorUnits - repriced.AmountPaid.MinorUnits
+	if !proto.Equal(payments.remaining(upgraded.UpgradePayments[0].Reference), eur(difference)) || !proto.Equal(upgraded.AmountPaid, upgraded.Price) {
+		t.Errorf("Expected a charge of %d on the amount paid, got %v", difference, upgraded)
+	}
+
+	// Saver fares are upgraded in place and never refunded.
+	saver := purchase("test@example.com", "S", "saver")
+	upgraded, err = server.UpgradeTicket(ctx, &trainService.UpgradeTicketRequest{BookingReference: saver.BookingReference, FareClass: "standard"})
+	if err != nil {
+		t.Fatalf("UpgradeTicket failed: %v", err)
+	}
+	if upgraded.Section != "S" || !proto.Equal(upgraded.Seat, saver.Seat) || upgraded.Price.MinorUnits <= saver.Price.MinorUnits {
+		t.Errorf("Expected the saver ticket upgraded in its seat, got %v", upgraded)
+	}
+	saver = purchase("saver@example.com", "S", "saver")
+	cancelled, err = server.CancelBooking(ctx, &trainService.BookingReference{Reference: saver.BookingReference})
+	if err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	if cancelled.Refund.Status != trainService.RefundStatus_NOT_REFUNDED {
+		t.Errorf("Expected no refund of a saver fare, got %v", cancelled.Refund)
+	}
+}
+
+func TestUpgradeTicketErrors(t *testing.T) {
+	payments := newFakePaymentProvider(paymentApprove)
+	server := &TrainServer{store: newRouteStore(), layouts: testLayouts, payments: payments, duplicates: duplicateAllow}
+	ctx := context.Background()
+
+	req := testSchedule("IC101", "2024-03-04", "09:30")
+	req.Sections = nil
+	req.Layout = "intercity"
+	departure := createDeparture(t, server, req)
+	var booked []*trainService.Ticket
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		ticket := testJourney(email, departure.Id, "S")
+		ticket, err := server.PurchaseTicket(ctx, ticket)
+		if err != nil {
+			t.Fatalf("PurchaseTicket failed: %v", err)
+		}
+		booked = append(booked, ticket)
+	}
+
+	// A declined charge leaves the ticket as it was and frees the held seat.
+	payments.outcome = paymentDecline
+	if _, err := server.UpgradeTicket(ctx, &trainService.UpgradeTicketRequest{BookingReference: booked[0].BookingReference, FareClass: "first"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for a declined payment, got %v", err)
+	}
+	if ticket, err := server.store.FindTicket(booked[0].BookingReference); err != nil || !proto.Equal(ticket, booked[0]) {
+		t.Errorf("Expected the ticket unchanged, got %v (%v)", ticket, err)
+	}
+	if len(server.store.Holds()) != 0 || server.store.SeatCount(departure.Id, "F") != 2 {
+		t.Errorf("Expected the first class seat released, got holds %v", server.store.Holds())
+	}
+	payments.outcome = paymentApprove
+
+	// The other two passengers fill the first class coach.
+	for _, ticket := range booked[1:] {
+		if _, err := server.UpgradeTicket(ctx, &trainService.UpgradeTicketRequest{BookingReference: ticket.BookingReference, FareClass: "first"}); err != nil {
+			t.Fatalf("UpgradeTicket failed: %v", err)
+		}
+	}
+	tests := []struct {
+		name         string
+		request      *trainService.UpgradeTicketRequest
+		expectedCode codes.Code
+	}{
+		{name: "Nil request", request: nil, expectedCode: codes.InvalidArgument},
+		{name: "Missing fare class", request: &trainService.UpgradeTicketRequest{BookingReference: booked[0].BookingReference}, expectedCode: codes.InvalidArgument},
+		{name: "Unknown booking", request: &trainService.UpgradeTicketRequest{BookingReference: "NOPE", FareClass: "first"}, expectedCode: codes.NotFound},
+		{name: "Unknown fare class", request: &trainService.UpgradeTicketRequest{BookingReference: booked[0].BookingReference, FareClass: "sleeper"}, expectedCode: codes.InvalidArgument},
+		{name: "Section not selling the class", request: &trainService.UpgradeTicketRequest{BookingReference: booked[0].BookingReference, FareClass: "first", Section: "S"}, expectedCode: codes.InvalidArgument},
+		{name: "First class sold out", request: &trainService.UpgradeTicketRequest{BookingReference: booked[0].BookingReference, FareClass: "first"}, expectedCode: codes.ResourceExhausted},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := server.UpgradeTicket(ctx, tc.request); status.Code(err) != tc.expectedCode {
+				t.Errorf("Expected %v, got %v", tc.expectedCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func waitlistNotFound(id string) error {
+	return withDetails(codes.NotFound, "waitlist entry not found or no longer waiting", &errdetails.ResourceInfo{
+		ResourceType: "waitlist entry",
+		ResourceName: id,
+	})
+}
+
+// withPosition returns a copy of entry with its place in the queue for its
+// section filled in. Callers must hold s.mu.
+func (s *TrainServer) withPosition(entry *trainService.WaitlistEntry) *trainService.WaitlistEntry {
+	entry = proto.Clone(entry).(*trainService.WaitlistEntry)
+	entry.Position = 1
+	for _, other := range s.store.Waitlist() {
+		if other.Id == entry.Id {
+			break
+		}
+		if other.Ticket.DepartureId == entry.Ticket.DepartureId && other.Ticket.Section == entry.Ticket.Section {
+			entry.Position++
+		}
+	}
+	return entry
+}
+
+// JoinWaitlist queues a journey in a sold-out section for the next seat that
+// frees up on its leg. The journey is checked as PurchaseTicket would check
+// it, and sections with a free seat are refused so the passenger buys instead.
+func (s *TrainServer) JoinWaitlist(ctx context.Context, req *trainService.Ticket) (*trainService.WaitlistEntry, error) {
+	if err := s.validatePurchase(req); err != nil {
+		return nil, err
+	}
+	// The passenger gets whichever seat frees up, at the fare when it does.
+	req.Seat = nil
+	req.QuoteToken = ""
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticket := proto.Clone(req).(*trainService.Ticket)
+	_, err := s.prepareTicket(ticket)
+	if err == nil {
+		return nil, preconditionFailed("SEATS_AVAILABLE", "section:"+req.Section,
+			fmt.Sprintf("section %s has free seats, purchase a ticket instead", req.Section))
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		return nil, err
+	}
+	for _, entry := range s.store.Waitlist() {
+		if entry.Ticket.DepartureId == ticket.DepartureId && entry.Ticket.User.Email == req.User.Email {
+			return nil, withDetails(codes.AlreadyExists,
+				fmt.Sprintf("user with email %s is already on the waitlist for departure %s", req.User.Email, ticket.DepartureId),
+				&errdetails.ResourceInfo{ResourceType: "waitlist entry", ResourceName: entry.Id, Owner: req.User.Email})
+		}
+	}
+
+	id, err := newToken()
+	if err != nil {
+		return nil, internalError("failed to generate waitlist entry id", err)
+	}
+	entry := &trainService.WaitlistEntry{
+		Id:       id,
+		Ticket:   ticket,
+		JoinedAt: s.clock().UTC().Format(time.RFC3339),
+	}
+	if err := s.store.AddWaitlistEntry(entry); err != nil {
+		return nil, internalError("failed to save waitlist entry", err)
+	}
+	return s.withPosition(entry), nil
+}
+
+// LeaveWaitlist takes an entry off the waitlist, releasing the seat it was
+// offered, if any, to the next passenger.
+func (s *TrainServer) LeaveWaitlist(ctx context.Context, req *trainService.WaitlistRequest) (*trainService.WaitlistEntry, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("id field is empty", field{"id", req.Id}); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, err := s.store.FindWaitlistEntry(req.Id)
+	if errors.Is(err, errWaitlistNotFound) {
+		return nil, waitlistNotFound(req.Id)
+	}
+	if err != nil {
+		return nil, internalError("failed to find waitlist entry", err)
+	}
+	if entry.Offer != nil && s.paying[entry.Offer.Token] {
+		return nil, status.Error(codes.Aborted, "payment for the offered seat is being taken")
+	}
+	if _, err := s.store.RemoveWaitlistEntry(entry.Id); err != nil {
+		return nil, internalError("failed to remove waitlist entry", err)
+	}
+	if entry.Offer != nil {
+		if _, err := s.store.RemoveHold(entry.Offer.Token); err != nil && !errors.Is(err, errHoldNotFound) {
+			return nil, internalError("failed to release offered seat", err)
+		}
+		s.offerWaitlistSeats(entry.Ticket.DepartureId)
+	}
+	return entry, nil
+}
+
+// GetWaitlistEntry returns a waitlist entry with its position in the queue and
+// the seat offered to it, if any.
+func (s *TrainServer) GetWaitlistEntry(ctx context.Context, req *trainService.WaitlistRequest) (*trainService.WaitlistEntry, error) {
+	if req == nil {
+		return nil, nilRequest()
+	}
+	if err := requireFields("id field is empty", field{"id", req.Id}); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, err := s.store.FindWaitlistEntry(req.Id)
+	if errors.Is(err, errWaitlistNotFound) {
+		return nil, waitlistNotFound(req.Id)
+	}
+	if err != nil {
+		return nil, internalError("failed to find waitlist entry", err)
+	}
+	return s.withPosition(entry), nil
+}
+
+// offerWaitlistSeats settles the waitlist of departure after seats may have
+// been freed or booked. Entries whose offered hold is gone, because it was
+// confirmed or released, leave the queue, and entries still waiting are
+// offered a seat hold in the order they joined as long as their journey can
+// be seated. The streams watching departure are then told its free seats.
+// Failures are only logged, as the change that freed the seats has already
+// been made. Callers must hold s.mu.
+func (s *TrainServer) offerWaitlistSeats(departure string) {
+	defer s.publishAvailability(departure)
+	for _, entry := range s.store.Waitlist() {
+		if entry.Ticket.DepartureId != departure {
+			continue
+		}
+		if entry.Offer != nil {
+			if _, err := s.store.FindHold(entry.Offer.Token); errors.Is(err, errHoldNotFound) {
+				if _, err := s.store.RemoveWaitlistEntry(entry.Id); err != nil {
+					log.Printf("failed to remove settled waitlist entry %s: %v", entry.Id, err)
+				}
+			}
+			continue
+		}
+
+		ticket := proto.Clone(entry.Ticket).(*trainService.Ticket)
+		if _, err := s.prepareTicket(ticket); err != nil {
+			continue
+		}
+		hold, err := s.placeHold(ticket)
+		if err != nil {
+			log.Printf("failed to offer a seat to waitlist entry %s: %v", entry.Id, err)
+			return
+		}
+		offered := proto.Clone(entry).(*trainService.WaitlistEntry)
+		offered.Offer = hold
+		if err := s.store.UpdateWaitlistEntry(offered); err != nil {
+			log.Printf("failed to record the seat offered to waitlist entry %s: %v", entry.Id, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamir0nman/train/trainService"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWaitlist(t *testing.T) {
+	now := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	store := newRouteStore()
+	server := &TrainServer{store: store, duplicates: duplicateReject, now: func() time.Time { return now }}
+	ctx := context.Background()
+
+	// Section A has two seats.
+	departure := createDeparture(t, server, testSchedule("IC101", "2024-03-04", "09:30"))
+	journey := func(email string) *trainService.Ticket {
+		return testJourney(email, departure.Id, "A")
+	}
+	entry := func(id string) *trainService.WaitlistEntry {
+		t.Helper()
+		entry, err := server.GetWaitlistEntry(ctx, &trainService.WaitlistRequest{Id: id})
+		if err != nil {
+			t.Fatalf("GetWaitlistEntry failed: %v", err)
+		}
+		return entry
+	}
+
+	if _, err := server.JoinWaitlist(ctx, journey("early@example.com")); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition joining with seats free, got %v", err)
+	}
+	first, err := server.PurchaseTicket(ctx, journey("first@example.com"))
+	if err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+	if _, err := server.PurchaseTicket(ctx, journey("second@example.com")); err != nil {
+		t.Fatalf("PurchaseTicket failed: %v", err)
+	}
+
+	third, err := server.JoinWaitlist(ctx, journey("third@example.com"))
+	if err != nil {
+		t.Fatalf("JoinWaitlist failed: %v", err)
+	}
+	fourth, err := server.JoinWaitlist(ctx, journey("fourth@example.com"))
+	if err != nil {
+		t.Fatalf("JoinWaitlist failed: %v", err)
+	}
+	if third.Position != 1 || fourth.Position != 2 {
+		t.Errorf("Expected positions 1 and 2, got %d and %d", third.Position, fourth.Position)
+	}
+	if _, err := server.JoinWaitlist(ctx, journey("third@example.com")); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists joining twice, got %v", err)
+	}
+
+	// A cancellation offers the seat to the head of the queue and nobody else.
+	if _, err := server.CancelBooking(ctx, &trainService.BookingReference{Reference: first.BookingReference}); err != nil {
+		t.Fatalf("CancelBooking failed: %v", err)
+	}
+	offer := entry(third.Id).Offer
+	if offer == nil || keyOf(offer.Ticket.Seat) != keyOf(first.Seat) {
+		t.Fatalf("Expected the freed seat offered to the first in the queue, got %v", offer)
+	}
+	if entry(fourth.Id).Offer != nil || store.SeatCount(departure.Id, "A") != 0 {
+		t.Errorf("Expected the seat held for the offer only")
+	}
+	if _, err := server.PurchaseTicket(ctx, journey("other@example.com")); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected an offered seat not to be sold, got %v", err)
+	}
+
+	ticket, err := server.ConfirmBooking(ctx, &trainService.ConfirmBookingRequest{HoldToken: offer.Token})
+	if err != nil {
+		t.Fatalf("ConfirmBooking failed: %v", err)
+	}
+	if ticket.User.Email != "third@example.com" {
+		t.Errorf("Expected the ticket booked for the waitlisted passenger, got %v", ticket)
+	}
+	if _, err := server.GetWaitlistEntry(ctx, &trainService.WaitlistRequest{Id: third.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected a booked entry to leave the waitlist, got %v", err)
+	}
+	if position := entry(fourth.Id).Position; position != 1 {
+		t.Errorf("Expected the next passenger to move up, got position %d", position)
+	}
+
+	// Added capacity is offered too.
+	resized, err := server.ResizeSection(ctx, &trainService.ResizeSectionRequest{DepartureId: departure.Id, Section: "A", Rows: 1, SeatsPerRow: 3})
+	if err != nil {
+		t.Fatalf("ResizeSection failed: %v", err)
+	}
+	if resized.AvailableSeats["A"] != 0 || entry(fourth.Id).Offer == nil {
+		t.Errorf("Expected the new seat offered to the waitlist, got %v", resized.AvailableSeats)
+	}
+
+	// Leaving with an offer passes the seat on.
+	fifth, err := server.JoinWaitlist(ctx, journey("fifth@example.com"))
+	if err != nil {
+		t.Fatalf("JoinWaitlist failed: %v", err)
+	}
+	if _, err := server.LeaveWaitlist(ctx, &trainService.WaitlistRequest{Id: fourth.Id}); err != nil {
+		t.Fatalf("LeaveWaitlist failed: %v", err)
+	}
+	if entry(fifth.Id).Offer == nil {
+		t.Errorf("Expected the released seat offered to the next passenger")
+	}
+
+	// An offer that runs out drops the entry and frees the seat.
+	now = now.Add(defaultHoldTTL)
+	if _, err := server.releaseExpiredHolds(); err != nil {
+		t.Fatalf("releaseExpiredHolds failed: %v", err)
+	}
+	if _, err := server.GetWaitlistEntry(ctx, &trainService.WaitlistRequest{Id: fifth.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected an expired offer to end the entry, got %v", err)
+	}
+	if store.SeatCount(departure.Id, "A") != 1 {
+		t.Errorf("Expected the seat back on sale, got %d", store.SeatCount(departure.Id, "A"))
+	}
+}
+
+func TestWaitlistErrors(t *testing.T) {
+	server := &TrainServer{store: newTestStore(testLayout, nil)}
+	ctx := context.Background()
+
+	for _, req := range []*trainService.WaitlistRequest{nil, {}} {
+		if _, err := server.GetWaitlistEntry(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+		if _, err := server.LeaveWaitlist(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+	if _, err := server.LeaveWaitlist(ctx, &trainService.WaitlistRequest{Id: "missing"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	resizes := []struct {
+		request      *trainService.ResizeSectionRequest
+		expectedCode codes.Code
+	}{
+		{request: nil, expectedCode: codes.InvalidArgument},
+		{request: &trainService.ResizeSectionRequest{Section: "A"}, expectedCode: codes.InvalidArgument},
+		{request: &trainService.ResizeSectionRequest{Section: "C", Rows: 9, SeatsPerRow: 9}, expectedCode: codes.InvalidArgument},
+		{request: &trainService.ResizeSectionRequest{Section: "A", Rows: 1, SeatsPerRow: 9}, expectedCode: codes.FailedPrecondition},
+		{request: &trainService.ResizeSectionRequest{DepartureId: "missing", Section: "A", Rows: 9, SeatsPerRow: 9}, expectedCode: codes.NotFound},
+	}
+	for _, tc := range resizes {
+		if _, err := server.ResizeSection(ctx, tc.request); status.Code(err) != tc.expectedCode {
+			t.Errorf("Expected %v for %v, got %v", tc.expectedCode, tc.request, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package trainService
+
+import (
+	"fmt"
+	"math"
+)
+
+// currencyDigits lists the ISO 4217 currencies whose minor unit is not a
+// hundredth of the major unit.
+var currencyDigits = map[string]int{
+	"BHD": 3, "CLP": 0, "ISK": 0, "JOD": 3, "JPY": 0,
+	"KRW": 0, "KWD": 3, "OMR": 3, "TND": 3, "VND": 0,
+}
+
+// CurrencyDigits returns the number of decimal digits of the minor unit of a
+// currency, e.g. 2 for EUR and 0 for JPY.
+func CurrencyDigits(currencyCode string) int {
+	if digits, ok := currencyDigits[currencyCode]; ok {
+		return digits
+	}
+	return 2
+}
+
+// FormatMoney formats m as its major units and currency code, e.g.
+// "79.25 EUR".
+func FormatMoney(m *Money) string {
+	digits := CurrencyDigits(m.GetCurrencyCode())
+	return fmt.Sprintf("%.*f %s", digits, float64(m.GetMinorUnits())/math.Pow10(digits), m.GetCurrencyCode())
+}
@@ -24,6 +24,34 @@ const (
 	TrainService_GetUsersBySection_FullMethodName = "/trainService.TrainService/GetUsersBySection"
 	TrainService_CancelTicket_FullMethodName      = "/trainService.TrainService/CancelTicket"
 	TrainService_ModifyUserSeat_FullMethodName    = "/trainService.TrainService/ModifyUserSeat"
+	TrainService_GrantSwapConsent_FullMethodName  = "/trainService.TrainService/GrantSwapConsent"
+	TrainService_SwapSeats_FullMethodName         = "/trainService.TrainService/SwapSeats"
+	TrainService_GetBooking_FullMethodName        = "/trainService.TrainService/GetBooking"
+	TrainService_CancelBooking_FullMethodName     = "/trainService.TrainService/CancelBooking"
+	TrainService_GetUserBookings_FullMethodName   = "/trainService.TrainService/GetUserBookings"
+	TrainService_CreateDeparture_FullMethodName   = "/trainService.TrainService/CreateDeparture"
+	TrainService_ListDepartures_FullMethodName    = "/trainService.TrainService/ListDepartures"
+	TrainService_AddStation_FullMethodName        = "/trainService.TrainService/AddStation"
+	TrainService_ListStations_FullMethodName      = "/trainService.TrainService/ListStations"
+	TrainService_CreateRoute_FullMethodName       = "/trainService.TrainService/CreateRoute"
+	TrainService_ListRoutes_FullMethodName        = "/trainService.TrainService/ListRoutes"
+	TrainService_QuoteFare_FullMethodName         = "/trainService.TrainService/QuoteFare"
+	TrainService_CreatePromoCode_FullMethodName   = "/trainService.TrainService/CreatePromoCode"
+	TrainService_ListPromoCodes_FullMethodName    = "/trainService.TrainService/ListPromoCodes"
+	TrainService_HoldSeat_FullMethodName          = "/trainService.TrainService/HoldSeat"
+	TrainService_ConfirmBooking_FullMethodName    = "/trainService.TrainService/ConfirmBooking"
+	TrainService_JoinWaitlist_FullMethodName      = "/trainService.TrainService/JoinWaitlist"
+	TrainService_LeaveWaitlist_FullMethodName     = "/trainService.TrainService/LeaveWaitlist"
+	TrainService_GetWaitlistEntry_FullMethodName  = "/trainService.TrainService/GetWaitlistEntry"
+	TrainService_ResizeSection_FullMethodName     = "/trainService.TrainService/ResizeSection"
+	TrainService_PurchaseGroup_FullMethodName     = "/trainService.TrainService/PurchaseGroup"
+	TrainService_GetGroupBooking_FullMethodName   = "/trainService.TrainService/GetGroupBooking"
+	TrainService_ListLayouts_FullMethodName       = "/trainService.TrainService/ListLayouts"
+	TrainService_UpgradeTicket_FullMethodName     = "/trainService.TrainService/UpgradeTicket"
+	TrainService_CheckIn_FullMethodName           = "/trainService.TrainService/CheckIn"
+	TrainService_CloseCheckIn_FullMethodName      = "/trainService.TrainService/CloseCheckIn"
+	TrainService_GetNoShowStats_FullMethodName    = "/trainService.TrainService/GetNoShowStats"
+	TrainService_WatchAvailability_FullMethodName = "/trainService.TrainService/WatchAvailability"
 )
 
 // TrainServiceClient is the client API for TrainService service.
@@ -35,6 +63,34 @@ type TrainServiceClient interface {
 	GetUsersBySection(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (TrainService_GetUsersBySectionClient, error)
 	CancelTicket(ctx context.Context, in *User, opts ...grpc.CallOption) (*Ticket, error)
 	ModifyUserSeat(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*Ticket, error)
+	GrantSwapConsent(ctx context.Context, in *SwapConsentRequest, opts ...grpc.CallOption) (*SwapConsent, error)
+	SwapSeats(ctx context.Context, in *SwapSeatsRequest, opts ...grpc.CallOption) (*SwapSeatsResponse, error)
+	GetBooking(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*Ticket, error)
+	CancelBooking(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*Ticket, error)
+	GetUserBookings(ctx context.Context, in *User, opts ...grpc.CallOption) (TrainService_GetUserBookingsClient, error)
+	CreateDeparture(ctx context.Context, in *Departure, opts ...grpc.CallOption) (*Departure, error)
+	ListDepartures(ctx context.Context, in *ListDeparturesRequest, opts ...grpc.CallOption) (TrainService_ListDeparturesClient, error)
+	AddStation(ctx context.Context, in *Station, opts ...grpc.CallOption) (*Station, error)
+	ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (TrainService_ListStationsClient, error)
+	CreateRoute(ctx context.Context, in *Route, opts ...grpc.CallOption) (*Route, error)
+	ListRoutes(ctx context.Context, in *ListRoutesRequest, opts ...grpc.CallOption) (TrainService_ListRoutesClient, error)
+	QuoteFare(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*FareQuote, error)
+	CreatePromoCode(ctx context.Context, in *PromoCode, opts ...grpc.CallOption) (*PromoCode, error)
+	ListPromoCodes(ctx context.Context, in *ListPromoCodesRequest, opts ...grpc.CallOption) (TrainService_ListPromoCodesClient, error)
+	HoldSeat(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*SeatHold, error)
+	ConfirmBooking(ctx context.Context, in *ConfirmBookingRequest, opts ...grpc.CallOption) (*Ticket, error)
+	JoinWaitlist(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, in *WaitlistRequest, opts ...grpc.CallOption) (*WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, in *WaitlistRequest, opts ...grpc.CallOption) (*WaitlistEntry, error)
+	ResizeSection(ctx context.Context, in *ResizeSectionRequest, opts ...grpc.CallOption) (*Departure, error)
+	PurchaseGroup(ctx context.Context, in *GroupBookingRequest, opts ...grpc.CallOption) (*GroupBooking, error)
+	GetGroupBooking(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*GroupBooking, error)
+	ListLayouts(ctx context.Context, in *ListLayoutsRequest, opts ...grpc.CallOption) (TrainService_ListLayoutsClient, error)
+	UpgradeTicket(ctx context.Context, in *UpgradeTicketRequest, opts ...grpc.CallOption) (*Ticket, error)
+	CheckIn(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*Ticket, error)
+	CloseCheckIn(ctx context.Context, in *CloseCheckInRequest, opts ...grpc.CallOption) (*CheckInReport, error)
+	GetNoShowStats(ctx context.Context, in *NoShowStatsRequest, opts ...grpc.CallOption) (*NoShowStats, error)
+	WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (TrainService_WatchAvailabilityClient, error)
 }
 
 type trainServiceClient struct {
@@ -113,6 +169,419 @@ func (c *trainServiceClient) ModifyUserSeat(ctx context.Context, in *Ticket, opt
 	return out, nil
 }
 
+func (c *trainServiceClient) GrantSwapConsent(ctx context.Context, in *SwapConsentRequest, opts ...grpc.CallOption) (*SwapConsent, error) {
+	out := new(SwapConsent)
+	err := c.cc.Invoke(ctx, TrainService_GrantSwapConsent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) SwapSeats(ctx context.Context, in *SwapSeatsRequest, opts ...grpc.CallOption) (*SwapSeatsResponse, error) {
+	out := new(SwapSeatsResponse)
+	err := c.cc.Invoke(ctx, TrainService_SwapSeats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) GetBooking(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*Ticket, error) {
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, TrainService_GetBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) CancelBooking(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*Ticket, error) {
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, TrainService_CancelBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) GetUserBookings(ctx context.Context, in *User, opts ...grpc.CallOption) (TrainService_GetUserBookingsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[1], TrainService_GetUserBookings_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceGetUserBookingsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_GetUserBookingsClient interface {
+	Recv() (*Ticket, error)
+	grpc.ClientStream
+}
+
+type trainServiceGetUserBookingsClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceGetUserBookingsClient) Recv() (*Ticket, error) {
+	m := new(Ticket)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainServiceClient) CreateDeparture(ctx context.Context, in *Departure, opts ...grpc.CallOption) (*Departure, error) {
+	out := new(Departure)
+	err := c.cc.Invoke(ctx, TrainService_CreateDeparture_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ListDepartures(ctx context.Context, in *ListDeparturesRequest, opts ...grpc.CallOption) (TrainService_ListDeparturesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[2], TrainService_ListDepartures_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceListDeparturesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_ListDeparturesClient interface {
+	Recv() (*Departure, error)
+	grpc.ClientStream
+}
+
+type trainServiceListDeparturesClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceListDeparturesClient) Recv() (*Departure, error) {
+	m := new(Departure)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainServiceClient) AddStation(ctx context.Context, in *Station, opts ...grpc.CallOption) (*Station, error) {
+	out := new(Station)
+	err := c.cc.Invoke(ctx, TrainService_AddStation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ListStations(ctx context.Context, in *ListStationsRequest, opts ...grpc.CallOption) (TrainService_ListStationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[3], TrainService_ListStations_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceListStationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_ListStationsClient interface {
+	Recv() (*Station, error)
+	grpc.ClientStream
+}
+
+type trainServiceListStationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceListStationsClient) Recv() (*Station, error) {
+	m := new(Station)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainServiceClient) CreateRoute(ctx context.Context, in *Route, opts ...grpc.CallOption) (*Route, error) {
+	out := new(Route)
+	err := c.cc.Invoke(ctx, TrainService_CreateRoute_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ListRoutes(ctx context.Context, in *ListRoutesRequest, opts ...grpc.CallOption) (TrainService_ListRoutesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[4], TrainService_ListRoutes_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceListRoutesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_ListRoutesClient interface {
+	Recv() (*Route, error)
+	grpc.ClientStream
+}
+
+type trainServiceListRoutesClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceListRoutesClient) Recv() (*Route, error) {
+	m := new(Route)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainServiceClient) QuoteFare(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*FareQuote, error) {
+	out := new(FareQuote)
+	err := c.cc.Invoke(ctx, TrainService_QuoteFare_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) CreatePromoCode(ctx context.Context, in *PromoCode, opts ...grpc.CallOption) (*PromoCode, error) {
+	out := new(PromoCode)
+	err := c.cc.Invoke(ctx, TrainService_CreatePromoCode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ListPromoCodes(ctx context.Context, in *ListPromoCodesRequest, opts ...grpc.CallOption) (TrainService_ListPromoCodesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[5], TrainService_ListPromoCodes_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceListPromoCodesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_ListPromoCodesClient interface {
+	Recv() (*PromoCode, error)
+	grpc.ClientStream
+}
+
+type trainServiceListPromoCodesClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceListPromoCodesClient) Recv() (*PromoCode, error) {
+	m := new(PromoCode)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainServiceClient) HoldSeat(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*SeatHold, error) {
+	out := new(SeatHold)
+	err := c.cc.Invoke(ctx, TrainService_HoldSeat_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ConfirmBooking(ctx context.Context, in *ConfirmBookingRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, TrainService_ConfirmBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) JoinWaitlist(ctx context.Context, in *Ticket, opts ...grpc.CallOption) (*WaitlistEntry, error) {
+	out := new(WaitlistEntry)
+	err := c.cc.Invoke(ctx, TrainService_JoinWaitlist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) LeaveWaitlist(ctx context.Context, in *WaitlistRequest, opts ...grpc.CallOption) (*WaitlistEntry, error) {
+	out := new(WaitlistEntry)
+	err := c.cc.Invoke(ctx, TrainService_LeaveWaitlist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) GetWaitlistEntry(ctx context.Context, in *WaitlistRequest, opts ...grpc.CallOption) (*WaitlistEntry, error) {
+	out := new(WaitlistEntry)
+	err := c.cc.Invoke(ctx, TrainService_GetWaitlistEntry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ResizeSection(ctx context.Context, in *ResizeSectionRequest, opts ...grpc.CallOption) (*Departure, error) {
+	out := new(Departure)
+	err := c.cc.Invoke(ctx, TrainService_ResizeSection_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) PurchaseGroup(ctx context.Context, in *GroupBookingRequest, opts ...grpc.CallOption) (*GroupBooking, error) {
+	out := new(GroupBooking)
+	err := c.cc.Invoke(ctx, TrainService_PurchaseGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) GetGroupBooking(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*GroupBooking, error) {
+	out := new(GroupBooking)
+	err := c.cc.Invoke(ctx, TrainService_GetGroupBooking_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) ListLayouts(ctx context.Context, in *ListLayoutsRequest, opts ...grpc.CallOption) (TrainService_ListLayoutsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[6], TrainService_ListLayouts_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceListLayoutsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_ListLayoutsClient interface {
+	Recv() (*TrainLayout, error)
+	grpc.ClientStream
+}
+
+type trainServiceListLayoutsClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceListLayoutsClient) Recv() (*TrainLayout, error) {
+	m := new(TrainLayout)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trainServiceClient) UpgradeTicket(ctx context.Context, in *UpgradeTicketRequest, opts ...grpc.CallOption) (*Ticket, error) {
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, TrainService_UpgradeTicket_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) CheckIn(ctx context.Context, in *BookingReference, opts ...grpc.CallOption) (*Ticket, error) {
+	out := new(Ticket)
+	err := c.cc.Invoke(ctx, TrainService_CheckIn_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) CloseCheckIn(ctx context.Context, in *CloseCheckInRequest, opts ...grpc.CallOption) (*CheckInReport, error) {
+	out := new(CheckInReport)
+	err := c.cc.Invoke(ctx, TrainService_CloseCheckIn_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) GetNoShowStats(ctx context.Context, in *NoShowStatsRequest, opts ...grpc.CallOption) (*NoShowStats, error) {
+	out := new(NoShowStats)
+	err := c.cc.Invoke(ctx, TrainService_GetNoShowStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainServiceClient) WatchAvailability(ctx context.Context, in *WatchAvailabilityRequest, opts ...grpc.CallOption) (TrainService_WatchAvailabilityClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TrainService_ServiceDesc.Streams[7], TrainService_WatchAvailability_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trainServiceWatchAvailabilityClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrainService_WatchAvailabilityClient interface {
+	Recv() (*SeatAvailability, error)
+	grpc.ClientStream
+}
+
+type trainServiceWatchAvailabilityClient struct {
+	grpc.ClientStream
+}
+
+func (x *trainServiceWatchAvailabilityClient) Recv() (*SeatAvailability, error) {
+	m := new(SeatAvailability)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // TrainServiceServer is the server API for TrainService service.
 // All implementations must embed UnimplementedTrainServiceServer
 // for forward compatibility
@@ -122,134 +591,771 @@ type TrainServiceServer interface {
 	GetUsersBySection(*Ticket, TrainService_GetUsersBySectionServer) error
 	CancelTicket(context.Context, *User) (*Ticket, error)
 	ModifyUserSeat(context.Context, *Ticket) (*Ticket, error)
+	GrantSwapConsent(context.Context, *SwapConsentRequest) (*SwapConsent, error)
+	SwapSeats(context.Context, *SwapSeatsRequest) (*SwapSeatsResponse, error)
+	GetBooking(context.Context, *BookingReference) (*Ticket, error)
+	CancelBooking(context.Context, *BookingReference) (*Ticket, error)
+	GetUserBookings(*User, TrainService_GetUserBookingsServer) error
+	CreateDeparture(context.Context, *Departure) (*Departure, error)
+	ListDepartures(*ListDeparturesRequest, TrainService_ListDeparturesServer) error
+	AddStation(context.Context, *Station) (*Station, error)
+	ListStations(*ListStationsRequest, TrainService_ListStationsServer) error
+	CreateRoute(context.Context, *Route) (*Route, error)
+	ListRoutes(*ListRoutesRequest, TrainService_ListRoutesServer) error
+	QuoteFare(context.Context, *Ticket) (*FareQuote, error)
+	CreatePromoCode(context.Context, *PromoCode) (*PromoCode, error)
+	ListPromoCodes(*ListPromoCodesRequest, TrainService_ListPromoCodesServer) error
+	HoldSeat(context.Context, *Ticket) (*SeatHold, error)
+	ConfirmBooking(context.Context, *ConfirmBookingRequest) (*Ticket, error)
+	JoinWaitlist(context.Context, *Ticket) (*WaitlistEntry, error)
+	LeaveWaitlist(context.Context, *WaitlistRequest) (*WaitlistEntry, error)
+	GetWaitlistEntry(context.Context, *WaitlistRequest) (*WaitlistEntry, error)
+	ResizeSection(context.Context, *ResizeSectionRequest) (*Departure, error)
+	PurchaseGroup(context.Context, *GroupBookingRequest) (*GroupBooking, error)
+	GetGroupBooking(context.Context, *BookingReference) (*GroupBooking, error)
+	ListLayouts(*ListLayoutsRequest, TrainService_ListLayoutsServer) error
+	UpgradeTicket(context.Context, *UpgradeTicketRequest) (*Ticket, error)
+	CheckIn(context.Context, *BookingReference) (*Ticket, error)
+	CloseCheckIn(context.Context, *CloseCheckInRequest) (*CheckInReport, error)
+	GetNoShowStats(context.Context, *NoShowStatsRequest) (*NoShowStats, error)
+	WatchAvailability(*WatchAvailabilityRequest, TrainService_WatchAvailabilityServer) error
+	mustEmbedUnimplementedTrainServiceServer()
+}
+
+// UnimplementedTrainServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTrainServiceServer struct {
+}
+
+func (UnimplementedTrainServiceServer) PurchaseTicket(context.Context, *Ticket) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurchaseTicket not implemented")
+}
+func (UnimplementedTrainServiceServer) GetReceipt(context.Context, *User) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceipt not implemented")
+}
+func (UnimplementedTrainServiceServer) GetUsersBySection(*Ticket, TrainService_GetUsersBySectionServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetUsersBySection not implemented")
+}
+func (UnimplementedTrainServiceServer) CancelTicket(context.Context, *User) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTicket not implemented")
+}
+func (UnimplementedTrainServiceServer) ModifyUserSeat(context.Context, *Ticket) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyUserSeat not implemented")
+}
+func (UnimplementedTrainServiceServer) GrantSwapConsent(context.Context, *SwapConsentRequest) (*SwapConsent, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantSwapConsent not implemented")
+}
+func (UnimplementedTrainServiceServer) SwapSeats(context.Context, *SwapSeatsRequest) (*SwapSeatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SwapSeats not implemented")
+}
+func (UnimplementedTrainServiceServer) GetBooking(context.Context, *BookingReference) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBooking not implemented")
+}
+func (UnimplementedTrainServiceServer) CancelBooking(context.Context, *BookingReference) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelBooking not implemented")
+}
+func (UnimplementedTrainServiceServer) GetUserBookings(*User, TrainService_GetUserBookingsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetUserBookings not implemented")
+}
+func (UnimplementedTrainServiceServer) CreateDeparture(context.Context, *Departure) (*Departure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDeparture not implemented")
+}
+func (UnimplementedTrainServiceServer) ListDepartures(*ListDeparturesRequest, TrainService_ListDeparturesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDepartures not implemented")
+}
+func (UnimplementedTrainServiceServer) AddStation(context.Context, *Station) (*Station, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddStation not implemented")
+}
+func (UnimplementedTrainServiceServer) ListStations(*ListStationsRequest, TrainService_ListStationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListStations not implemented")
+}
+func (UnimplementedTrainServiceServer) CreateRoute(context.Context, *Route) (*Route, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRoute not implemented")
+}
+func (UnimplementedTrainServiceServer) ListRoutes(*ListRoutesRequest, TrainService_ListRoutesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListRoutes not implemented")
+}
+func (UnimplementedTrainServiceServer) QuoteFare(context.Context, *Ticket) (*FareQuote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteFare not implemented")
+}
+func (UnimplementedTrainServiceServer) CreatePromoCode(context.Context, *PromoCode) (*PromoCode, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePromoCode not implemented")
+}
+func (UnimplementedTrainServiceServer) ListPromoCodes(*ListPromoCodesRequest, TrainService_ListPromoCodesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPromoCodes not implemented")
+}
+func (UnimplementedTrainServiceServer) HoldSeat(context.Context, *Ticket) (*SeatHold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HoldSeat not implemented")
+}
+func (UnimplementedTrainServiceServer) ConfirmBooking(context.Context, *ConfirmBookingRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmBooking not implemented")
+}
+func (UnimplementedTrainServiceServer) JoinWaitlist(context.Context, *Ticket) (*WaitlistEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinWaitlist not implemented")
+}
+func (UnimplementedTrainServiceServer) LeaveWaitlist(context.Context, *WaitlistRequest) (*WaitlistEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveWaitlist not implemented")
+}
+func (UnimplementedTrainServiceServer) GetWaitlistEntry(context.Context, *WaitlistRequest) (*WaitlistEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWaitlistEntry not implemented")
+}
+func (UnimplementedTrainServiceServer) ResizeSection(context.Context, *ResizeSectionRequest) (*Departure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResizeSection not implemented")
+}
+func (UnimplementedTrainServiceServer) PurchaseGroup(context.Context, *GroupBookingRequest) (*GroupBooking, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurchaseGroup not implemented")
+}
+func (UnimplementedTrainServiceServer) GetGroupBooking(context.Context, *BookingReference) (*GroupBooking, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroupBooking not implemented")
+}
+func (UnimplementedTrainServiceServer) ListLayouts(*ListLayoutsRequest, TrainService_ListLayoutsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListLayouts not implemented")
+}
+func (UnimplementedTrainServiceServer) UpgradeTicket(context.Context, *UpgradeTicketRequest) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeTicket not implemented")
+}
+func (UnimplementedTrainServiceServer) CheckIn(context.Context, *BookingReference) (*Ticket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckIn not implemented")
+}
+func (UnimplementedTrainServiceServer) CloseCheckIn(context.Context, *CloseCheckInRequest) (*CheckInReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseCheckIn not implemented")
+}
+func (UnimplementedTrainServiceServer) GetNoShowStats(context.Context, *NoShowStatsRequest) (*NoShowStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNoShowStats not implemented")
+}
+func (UnimplementedTrainServiceServer) WatchAvailability(*WatchAvailabilityRequest, TrainService_WatchAvailabilityServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAvailability not implemented")
+}
+func (UnimplementedTrainServiceServer) mustEmbedUnimplementedTrainServiceServer() {}
+
+// UnsafeTrainServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrainServiceServer will
+// result in compilation errors.
+type UnsafeTrainServiceServer interface {
 	mustEmbedUnimplementedTrainServiceServer()
 }
 
-// UnimplementedTrainServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedTrainServiceServer struct {
+func RegisterTrainServiceServer(s grpc.ServiceRegistrar, srv TrainServiceServer) {
+	s.RegisterService(&TrainService_ServiceDesc, srv)
+}
+
+func _TrainService_PurchaseTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ticket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).PurchaseTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_PurchaseTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).PurchaseTicket(ctx, req.(*Ticket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GetReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).GetReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_GetReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).GetReceipt(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GetUsersBySection_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Ticket)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).GetUsersBySection(m, &trainServiceGetUsersBySectionServer{stream})
+}
+
+type TrainService_GetUsersBySectionServer interface {
+	Send(*Ticket) error
+	grpc.ServerStream
+}
+
+type trainServiceGetUsersBySectionServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainServiceGetUsersBySectionServer) Send(m *Ticket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrainService_CancelTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).CancelTicket(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_CancelTicket_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).CancelTicket(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_ModifyUserSeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ticket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).ModifyUserSeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_ModifyUserSeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).ModifyUserSeat(ctx, req.(*Ticket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GrantSwapConsent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapConsentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).GrantSwapConsent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_GrantSwapConsent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).GrantSwapConsent(ctx, req.(*SwapConsentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_SwapSeats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapSeatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).SwapSeats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_SwapSeats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).SwapSeats(ctx, req.(*SwapSeatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GetBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookingReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).GetBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_GetBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).GetBooking(ctx, req.(*BookingReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_CancelBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookingReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).CancelBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_CancelBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).CancelBooking(ctx, req.(*BookingReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GetUserBookings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(User)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).GetUserBookings(m, &trainServiceGetUserBookingsServer{stream})
+}
+
+type TrainService_GetUserBookingsServer interface {
+	Send(*Ticket) error
+	grpc.ServerStream
+}
+
+type trainServiceGetUserBookingsServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainServiceGetUserBookingsServer) Send(m *Ticket) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrainService_CreateDeparture_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Departure)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).CreateDeparture(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_CreateDeparture_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).CreateDeparture(ctx, req.(*Departure))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_ListDepartures_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDeparturesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).ListDepartures(m, &trainServiceListDeparturesServer{stream})
+}
+
+type TrainService_ListDeparturesServer interface {
+	Send(*Departure) error
+	grpc.ServerStream
+}
+
+type trainServiceListDeparturesServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainServiceListDeparturesServer) Send(m *Departure) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrainService_AddStation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Station)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).AddStation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_AddStation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).AddStation(ctx, req.(*Station))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_ListStations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListStationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).ListStations(m, &trainServiceListStationsServer{stream})
+}
+
+type TrainService_ListStationsServer interface {
+	Send(*Station) error
+	grpc.ServerStream
+}
+
+type trainServiceListStationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainServiceListStationsServer) Send(m *Station) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrainService_CreateRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Route)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).CreateRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_CreateRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).CreateRoute(ctx, req.(*Route))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_ListRoutes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRoutesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).ListRoutes(m, &trainServiceListRoutesServer{stream})
+}
+
+type TrainService_ListRoutesServer interface {
+	Send(*Route) error
+	grpc.ServerStream
+}
+
+type trainServiceListRoutesServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainServiceListRoutesServer) Send(m *Route) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrainService_QuoteFare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ticket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).QuoteFare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_QuoteFare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).QuoteFare(ctx, req.(*Ticket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_CreatePromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).CreatePromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_CreatePromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).CreatePromoCode(ctx, req.(*PromoCode))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedTrainServiceServer) PurchaseTicket(context.Context, *Ticket) (*Ticket, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PurchaseTicket not implemented")
-}
-func (UnimplementedTrainServiceServer) GetReceipt(context.Context, *User) (*Ticket, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetReceipt not implemented")
+func _TrainService_ListPromoCodes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPromoCodesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).ListPromoCodes(m, &trainServiceListPromoCodesServer{stream})
 }
-func (UnimplementedTrainServiceServer) GetUsersBySection(*Ticket, TrainService_GetUsersBySectionServer) error {
-	return status.Errorf(codes.Unimplemented, "method GetUsersBySection not implemented")
+
+type TrainService_ListPromoCodesServer interface {
+	Send(*PromoCode) error
+	grpc.ServerStream
 }
-func (UnimplementedTrainServiceServer) CancelTicket(context.Context, *User) (*Ticket, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CancelTicket not implemented")
+
+type trainServiceListPromoCodesServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedTrainServiceServer) ModifyUserSeat(context.Context, *Ticket) (*Ticket, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ModifyUserSeat not implemented")
+
+func (x *trainServiceListPromoCodesServer) Send(m *PromoCode) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedTrainServiceServer) mustEmbedUnimplementedTrainServiceServer() {}
 
-// UnsafeTrainServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to TrainServiceServer will
-// result in compilation errors.
-type UnsafeTrainServiceServer interface {
-	mustEmbedUnimplementedTrainServiceServer()
+func _TrainService_HoldSeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ticket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).HoldSeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_HoldSeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).HoldSeat(ctx, req.(*Ticket))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterTrainServiceServer(s grpc.ServiceRegistrar, srv TrainServiceServer) {
-	s.RegisterService(&TrainService_ServiceDesc, srv)
+func _TrainService_ConfirmBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).ConfirmBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_ConfirmBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).ConfirmBooking(ctx, req.(*ConfirmBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _TrainService_PurchaseTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _TrainService_JoinWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Ticket)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TrainServiceServer).PurchaseTicket(ctx, in)
+		return srv.(TrainServiceServer).JoinWaitlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TrainService_PurchaseTicket_FullMethodName,
+		FullMethod: TrainService_JoinWaitlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TrainServiceServer).PurchaseTicket(ctx, req.(*Ticket))
+		return srv.(TrainServiceServer).JoinWaitlist(ctx, req.(*Ticket))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TrainService_GetReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(User)
+func _TrainService_LeaveWaitlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitlistRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TrainServiceServer).GetReceipt(ctx, in)
+		return srv.(TrainServiceServer).LeaveWaitlist(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TrainService_GetReceipt_FullMethodName,
+		FullMethod: TrainService_LeaveWaitlist_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TrainServiceServer).GetReceipt(ctx, req.(*User))
+		return srv.(TrainServiceServer).LeaveWaitlist(ctx, req.(*WaitlistRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TrainService_GetUsersBySection_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Ticket)
+func _TrainService_GetWaitlistEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).GetWaitlistEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_GetWaitlistEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).GetWaitlistEntry(ctx, req.(*WaitlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_ResizeSection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResizeSectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).ResizeSection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_ResizeSection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).ResizeSection(ctx, req.(*ResizeSectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_PurchaseGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupBookingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).PurchaseGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_PurchaseGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).PurchaseGroup(ctx, req.(*GroupBookingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GetGroupBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookingReference)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).GetGroupBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_GetGroupBooking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).GetGroupBooking(ctx, req.(*BookingReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_ListLayouts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListLayoutsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(TrainServiceServer).GetUsersBySection(m, &trainServiceGetUsersBySectionServer{stream})
+	return srv.(TrainServiceServer).ListLayouts(m, &trainServiceListLayoutsServer{stream})
 }
 
-type TrainService_GetUsersBySectionServer interface {
-	Send(*Ticket) error
+type TrainService_ListLayoutsServer interface {
+	Send(*TrainLayout) error
 	grpc.ServerStream
 }
 
-type trainServiceGetUsersBySectionServer struct {
+type trainServiceListLayoutsServer struct {
 	grpc.ServerStream
 }
 
-func (x *trainServiceGetUsersBySectionServer) Send(m *Ticket) error {
+func (x *trainServiceListLayoutsServer) Send(m *TrainLayout) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _TrainService_CancelTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(User)
+func _TrainService_UpgradeTicket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeTicketRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TrainServiceServer).CancelTicket(ctx, in)
+		return srv.(TrainServiceServer).UpgradeTicket(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TrainService_CancelTicket_FullMethodName,
+		FullMethod: TrainService_UpgradeTicket_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TrainServiceServer).CancelTicket(ctx, req.(*User))
+		return srv.(TrainServiceServer).UpgradeTicket(ctx, req.(*UpgradeTicketRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TrainService_ModifyUserSeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Ticket)
+func _TrainService_CheckIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookingReference)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TrainServiceServer).ModifyUserSeat(ctx, in)
+		return srv.(TrainServiceServer).CheckIn(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: TrainService_ModifyUserSeat_FullMethodName,
+		FullMethod: TrainService_CheckIn_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TrainServiceServer).ModifyUserSeat(ctx, req.(*Ticket))
+		return srv.(TrainServiceServer).CheckIn(ctx, req.(*BookingReference))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_CloseCheckIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseCheckInRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).CloseCheckIn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_CloseCheckIn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).CloseCheckIn(ctx, req.(*CloseCheckInRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainService_GetNoShowStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NoShowStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainServiceServer).GetNoShowStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainService_GetNoShowStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainServiceServer).GetNoShowStats(ctx, req.(*NoShowStatsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrainService_WatchAvailability_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAvailabilityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrainServiceServer).WatchAvailability(m, &trainServiceWatchAvailabilityServer{stream})
+}
+
+type TrainService_WatchAvailabilityServer interface {
+	Send(*SeatAvailability) error
+	grpc.ServerStream
+}
+
+type trainServiceWatchAvailabilityServer struct {
+	grpc.ServerStream
+}
+
+func (x *trainServiceWatchAvailabilityServer) Send(m *SeatAvailability) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // TrainService_ServiceDesc is the grpc.ServiceDesc for TrainService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -273,6 +1379,90 @@ var TrainService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ModifyUserSeat",
 			Handler:    _TrainService_ModifyUserSeat_Handler,
 		},
+		{
+			MethodName: "GrantSwapConsent",
+			Handler:    _TrainService_GrantSwapConsent_Handler,
+		},
+		{
+			MethodName: "SwapSeats",
+			Handler:    _TrainService_SwapSeats_Handler,
+		},
+		{
+			MethodName: "GetBooking",
+			Handler:    _TrainService_GetBooking_Handler,
+		},
+		{
+			MethodName: "CancelBooking",
+			Handler:    _TrainService_CancelBooking_Handler,
+		},
+		{
+			MethodName: "CreateDeparture",
+			Handler:    _TrainService_CreateDeparture_Handler,
+		},
+		{
+			MethodName: "AddStation",
+			Handler:    _TrainService_AddStation_Handler,
+		},
+		{
+			MethodName: "CreateRoute",
+			Handler:    _TrainService_CreateRoute_Handler,
+		},
+		{
+			MethodName: "QuoteFare",
+			Handler:    _TrainService_QuoteFare_Handler,
+		},
+		{
+			MethodName: "CreatePromoCode",
+			Handler:    _TrainService_CreatePromoCode_Handler,
+		},
+		{
+			MethodName: "HoldSeat",
+			Handler:    _TrainService_HoldSeat_Handler,
+		},
+		{
+			MethodName: "ConfirmBooking",
+			Handler:    _TrainService_ConfirmBooking_Handler,
+		},
+		{
+			MethodName: "JoinWaitlist",
+			Handler:    _TrainService_JoinWaitlist_Handler,
+		},
+		{
+			MethodName: "LeaveWaitlist",
+			Handler:    _TrainService_LeaveWaitlist_Handler,
+		},
+		{
+			MethodName: "GetWaitlistEntry",
+			Handler:    _TrainService_GetWaitlistEntry_Handler,
+		},
+		{
+			MethodName: "ResizeSection",
+			Handler:    _TrainService_ResizeSection_Handler,
+		},
+		{
+			MethodName: "PurchaseGroup",
+			Handler:    _TrainService_PurchaseGroup_Handler,
+		},
+		{
+			MethodName: "GetGroupBooking",
+			Handler:    _TrainService_GetGroupBooking_Handler,
+		},
+		{
+			MethodName: "UpgradeTicket",
+			Handler:    _TrainService_UpgradeTicket_Handler,
+		},
+		{
+			MethodName: "CheckIn",
+			Handler:    _TrainService_CheckIn_Handler,
+		},
+		{
+			MethodName: "CloseCheckIn",
+			Handler:    _TrainService_CloseCheckIn_Handler,
+		},
+		{
+			MethodName: "GetNoShowStats",
+			Handler:    _TrainService_GetNoShowStats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -280,6 +1470,41 @@ var TrainService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TrainService_GetUsersBySection_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetUserBookings",
+			Handler:       _TrainService_GetUserBookings_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListDepartures",
+			Handler:       _TrainService_ListDepartures_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListStations",
+			Handler:       _TrainService_ListStations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListRoutes",
+			Handler:       _TrainService_ListRoutes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListPromoCodes",
+			Handler:       _TrainService_ListPromoCodes_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListLayouts",
+			Handler:       _TrainService_ListLayouts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchAvailability",
+			Handler:       _TrainService_WatchAvailability_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "train.proto",
 }